@@ -0,0 +1,101 @@
+package cache
+
+// Admission selects how a full cache decides whether a new key may displace
+// the eviction victim.
+type Admission int
+
+const (
+	// AdmitAll always admits the new key and evicts the victim.
+	AdmitAll Admission = iota
+	// TinyLFU admits a new key only when its estimated access frequency is
+	// higher than the victim's, keeping one-off keys from displacing popular
+	// ones.
+	TinyLFU
+)
+
+const sketchDepth = 4
+
+// countMinSketch estimates access frequencies with 4-bit counters packed
+// sixteen to a word. Once the number of recorded accesses reaches resetAt,
+// every counter is halved so that old popularity fades.
+type countMinSketch struct {
+	rows      [sketchDepth][]uint64
+	mask      uint64
+	hash      func(uint64) uint64
+	additions int
+	resetAt   int
+}
+
+func newCountMinSketch(capacity int) *countMinSketch {
+	counters := 64
+	for counters < capacity {
+		counters <<= 1
+	}
+	s := &countMinSketch{
+		mask:    uint64(counters - 1),
+		resetAt: 10 * max(capacity, 1),
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint64, counters/16)
+	}
+	return s
+}
+
+// slot returns the word and bit offset of hash's counter in row.
+func (s *countMinSketch) slot(hash uint64, row int) (int, uint) {
+	index := mix64(hash+uint64(row)*0x9e3779b97f4a7c15) & s.mask
+	return int(index >> 4), uint(index&15) * 4
+}
+
+func (s *countMinSketch) increment(hash uint64) {
+	for row := range s.rows {
+		word, shift := s.slot(hash, row)
+		if (s.rows[row][word]>>shift)&0xf < 0xf {
+			s.rows[row][word] += 1 << shift
+		}
+	}
+	s.additions++
+	if s.additions >= s.resetAt {
+		s.halve()
+	}
+}
+
+func (s *countMinSketch) estimate(hash uint64) uint64 {
+	estimate := uint64(0xf)
+	for row := range s.rows {
+		word, shift := s.slot(hash, row)
+		estimate = min(estimate, (s.rows[row][word]>>shift)&0xf)
+	}
+	return estimate
+}
+
+func (s *countMinSketch) halve() {
+	for row := range s.rows {
+		for word := range s.rows[row] {
+			s.rows[row][word] = (s.rows[row][word] >> 1) & 0x7777777777777777
+		}
+	}
+	s.additions /= 2
+}
+
+// admitter is the TinyLFU filter. It is only used under the cache's write
+// lock.
+type admitter[K comparable] struct {
+	sketch *countMinSketch
+	hasher func(K) uint64
+}
+
+func newAdmitter[K comparable](capacity int, hasher func(K) uint64) *admitter[K] {
+	if hasher == nil {
+		hasher = defaultHasher[K]()
+	}
+	return &admitter[K]{sketch: newCountMinSketch(capacity), hasher: hasher}
+}
+
+func (a *admitter[K]) record(key K) {
+	a.sketch.increment(a.hasher(key))
+}
+
+func (a *admitter[K]) admit(candidate, victim K) bool {
+	return a.sketch.estimate(a.hasher(candidate)) > a.sketch.estimate(a.hasher(victim))
+}
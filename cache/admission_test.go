@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type rejectingListener struct {
+	*CountingCacheListener[string]
+	rejected []string
+}
+
+func (l *rejectingListener) OnReject(key string) {
+	l.rejected = append(l.rejected, key)
+}
+
+func zipfHitRatio(trace []string, opts ...Option[string, string]) float64 {
+	backingStore := func(key string) (string, bool) { return key, true }
+	cache := NewLRUCache[string, string](100, time.Minute, backingStore, quietListener[string]{}, time.Minute, opts...)
+	defer cache.Close()
+	for _, key := range trace {
+		cache.Get(key)
+	}
+	stats := cache.Stats()
+	return float64(stats.Hits) / float64(stats.Hits+stats.Misses)
+}
+
+// Test Case 1: TinyLFU beats plain LRU on a Zipfian trace
+func TestTinyLFUHitRatio(t *testing.T) {
+	zipf := rand.NewZipf(rand.New(rand.NewSource(1)), 1.1, 1, 9999)
+	trace := make([]string, 100000)
+	for i := range trace {
+		trace[i] = fmt.Sprint("key", zipf.Uint64())
+	}
+
+	lru := zipfHitRatio(trace)
+	tinyLFU := zipfHitRatio(trace, WithAdmission[string, string](TinyLFU))
+	if tinyLFU <= lru {
+		t.Errorf("Expected TinyLFU hit ratio above %.3f, got %.3f", lru, tinyLFU)
+	}
+}
+
+// Test Case 2: A cold key cannot displace a popular victim
+func TestTinyLFURejects(t *testing.T) {
+	listener := &rejectingListener{CountingCacheListener: NewCountingCacheListener[string]()}
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second,
+		WithAdmission[string, string](TinyLFU))
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	for i := 0; i < 5; i++ {
+		cache.Get("key1")
+		cache.Get("key2")
+	}
+
+	cache.Put("key3", "value3")
+	if _, found := cache.GetOk("key3"); found {
+		t.Errorf("Expected 'key3' to be rejected")
+	}
+	if len(listener.rejected) != 1 || listener.rejected[0] != "key3" {
+		t.Errorf("Expected [key3], got %v", listener.rejected)
+	}
+	if value := cache.Stats().Rejections; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+
+	for i := 0; i < 10; i++ {
+		cache.Put("key3", "value3") // Repeated writes build up key3's frequency
+	}
+	if value := cache.Get("key3"); value != "value3" {
+		t.Errorf("Expected 'value3', got '%s'", value)
+	}
+	if value := listener.evictMap["key1"] + listener.evictMap["key2"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 3: Halving ages out old frequencies
+func TestCountMinSketchHalving(t *testing.T) {
+	sketch := newCountMinSketch(16)
+	for i := 0; i < 20; i++ {
+		sketch.increment(42)
+	}
+	if value := sketch.estimate(42); value != 15 {
+		t.Errorf("Expected '15', got '%d'", value)
+	}
+	sketch.halve()
+	if value := sketch.estimate(42); value != 7 {
+		t.Errorf("Expected '7', got '%d'", value)
+	}
+}
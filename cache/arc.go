@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"container/list"
+	"time"
+)
+
+const (
+	recentSegment uint8 = iota
+	frequentSegment
+)
+
+// ghostPolicy is implemented by policies that remember keys they have evicted.
+type ghostPolicy[K comparable, V any] interface {
+	// admitting is called before a full cache makes room for key, so that the
+	// policy can adapt to a ghost hit before it picks a victim.
+	admitting(key K)
+	// evicted records item, which has already been removed, as evicted.
+	evicted(item *CacheItem[K, V])
+}
+
+// ghost is an evicted key remembered by arcPolicy.
+type ghost[K comparable] struct {
+	key       K
+	evictedAt time.Time
+	frequent  bool
+}
+
+// arcPolicy is the adaptive replacement cache of Megiddo and Modha. recent (T1)
+// holds items seen once and frequent (T2) items seen at least twice. The
+// ghost lists B1 and B2 remember keys recently evicted from each, without
+// their values. A miss on a B1 ghost grows target, the share of the capacity
+// given to recent; a miss on a B2 ghost shrinks it. Ghosts older than ghostTTL
+// are ignored and dropped.
+type arcPolicy[K comparable, V any] struct {
+	recent, frequent         itemList[K, V]
+	recentGhosts, freqGhosts *list.List
+	ghosts                   map[K]*list.Element
+	capacity                 int
+	target                   int
+	ghostTTL                 time.Duration
+	clock                    Clock
+	pending                  K
+	pendingGhost             bool
+}
+
+func newARCPolicy[K comparable, V any](capacity int, ghostTTL time.Duration, clock Clock) *arcPolicy[K, V] {
+	p := &arcPolicy[K, V]{capacity: capacity, ghostTTL: ghostTTL, clock: clock}
+	p.clear()
+	return p
+}
+
+func (p *arcPolicy[K, V]) admitting(key K) {
+	p.pending, p.pendingGhost = key, false
+	element, found := p.ghosts[key]
+	if !found {
+		return
+	}
+	g := p.removeGhost(element)
+	if p.ghostTTL > 0 && p.clock.Now().Sub(g.evictedAt) > p.ghostTTL {
+		return
+	}
+
+	p.pendingGhost = true
+	if g.frequent {
+		p.target = max(p.target-max(p.recentGhosts.Len()/max(p.freqGhosts.Len(), 1), 1), 0)
+	} else {
+		p.target = min(p.target+max(p.freqGhosts.Len()/max(p.recentGhosts.Len(), 1), 1), p.capacity)
+	}
+}
+
+func (p *arcPolicy[K, V]) add(item *CacheItem[K, V]) {
+	if p.pendingGhost && p.pending == item.key {
+		item.segment = frequentSegment
+		p.frequent.pushFront(item)
+	} else {
+		item.segment = recentSegment
+		p.recent.pushFront(item)
+	}
+	var zeroKey K
+	p.pending, p.pendingGhost = zeroKey, false
+	// A key added without going through admitting must not leave a stale ghost.
+	if element, found := p.ghosts[item.key]; found {
+		p.removeGhost(element)
+	}
+}
+
+func (p *arcPolicy[K, V]) touch(item *CacheItem[K, V]) {
+	if item.segment == frequentSegment {
+		p.frequent.moveToFront(item)
+		return
+	}
+	p.recent.remove(item)
+	item.segment = frequentSegment
+	p.frequent.pushFront(item)
+}
+
+func (p *arcPolicy[K, V]) sharedHits() bool {
+	return false
+}
+
+func (p *arcPolicy[K, V]) touchShared(*CacheItem[K, V]) {}
+
+func (p *arcPolicy[K, V]) remove(item *CacheItem[K, V]) {
+	if item.segment == frequentSegment {
+		p.frequent.remove(item)
+	} else {
+		p.recent.remove(item)
+	}
+}
+
+func (p *arcPolicy[K, V]) evicted(item *CacheItem[K, V]) {
+	g := &ghost[K]{key: item.key, evictedAt: p.clock.Now(), frequent: item.segment == frequentSegment}
+	if g.frequent {
+		p.ghosts[g.key] = p.freqGhosts.PushFront(g)
+	} else {
+		p.ghosts[g.key] = p.recentGhosts.PushFront(g)
+	}
+
+	// Keep |T1|+|B1| and the total number of ghosts within the capacity.
+	for p.recentGhosts.Len() > 0 && p.recent.len+p.recentGhosts.Len() > p.capacity {
+		p.removeGhost(p.recentGhosts.Back())
+	}
+	for len(p.ghosts) > p.capacity {
+		if p.freqGhosts.Len() > 0 {
+			p.removeGhost(p.freqGhosts.Back())
+		} else {
+			p.removeGhost(p.recentGhosts.Back())
+		}
+	}
+}
+
+// resize adopts the new capacity, keeping the target within it and dropping
+// the oldest ghosts beyond it.
+func (p *arcPolicy[K, V]) resize(capacity int) {
+	p.capacity = capacity
+	p.target = min(p.target, capacity)
+	for len(p.ghosts) > capacity {
+		if p.recentGhosts.Len() > 0 {
+			p.removeGhost(p.recentGhosts.Back())
+		} else {
+			p.removeGhost(p.freqGhosts.Back())
+		}
+	}
+}
+
+func (p *arcPolicy[K, V]) removeGhost(element *list.Element) *ghost[K] {
+	g := element.Value.(*ghost[K])
+	if g.frequent {
+		p.freqGhosts.Remove(element)
+	} else {
+		p.recentGhosts.Remove(element)
+	}
+	delete(p.ghosts, g.key)
+	return g
+}
+
+// victims takes from recent while it is above its target size, or when
+// frequent is empty, and from frequent otherwise.
+func (p *arcPolicy[K, V]) victims(yield func(*CacheItem[K, V]) bool) {
+	segments := []*itemList[K, V]{&p.frequent, &p.recent}
+	if p.recent.len > 0 && (p.recent.len > p.target || p.frequent.len == 0) {
+		segments[0], segments[1] = segments[1], segments[0]
+	}
+	for _, segment := range segments {
+		for item := segment.back(); item != nil; item = segment.prev(item) {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+func (p *arcPolicy[K, V]) entries(yield func(*CacheItem[K, V]) bool) {
+	for _, segment := range []*itemList[K, V]{&p.frequent, &p.recent} {
+		for item := segment.front(); item != nil; item = segment.next(item) {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+func (p *arcPolicy[K, V]) clear() {
+	p.recent.init()
+	p.frequent.init()
+	p.recentGhosts = list.New()
+	p.freqGhosts = list.New()
+	p.ghosts = make(map[K]*list.Element)
+	p.target = 0
+}
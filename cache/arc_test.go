@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func traceHitRatio(cache *LRUCache[string, string], trace []string) float64 {
+	defer cache.Close()
+	for _, key := range trace {
+		cache.Get(key)
+	}
+	stats := cache.Stats()
+	return float64(stats.Hits) / float64(stats.Hits+stats.Misses)
+}
+
+// Test Case 1: ARC beats LRU on a working set interleaved with scans
+func TestARCHitRatio(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	var trace []string
+	scanned := 0
+	for round := 0; round < 100; round++ {
+		for i := 0; i < 300; i++ {
+			trace = append(trace, fmt.Sprint("hot", random.Intn(60)))
+		}
+		for i := 0; i < 150; i++ {
+			trace = append(trace, fmt.Sprint("scan", scanned))
+			scanned++
+		}
+	}
+
+	backingStore := func(key string) (string, bool) { return key, true }
+	lru := traceHitRatio(NewLRUCache[string, string](100, time.Minute, backingStore, quietListener[string]{}, time.Minute), trace)
+	arc := traceHitRatio(NewARCCache[string, string](100, time.Minute, backingStore, quietListener[string]{}, time.Minute), trace)
+	if arc <= lru {
+		t.Errorf("Expected ARC hit ratio above %.3f, got %.3f", lru, arc)
+	}
+}
+
+// Test Case 2: A ghost hit grows the recency target and lands in frequent
+func TestARCGhostHit(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewARCCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second)
+	policy := cache.policy.(*arcPolicy[string, string])
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3") // Evicts key1 into the recent ghost list
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if _, found := policy.ghosts["key1"]; !found {
+		t.Errorf("Expected 'key1' to be remembered as a ghost")
+	}
+
+	cache.Put("key1", "value1")
+	if value := policy.target; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if item := cache.cache["key1"]; item.segment != frequentSegment {
+		t.Errorf("Expected 'key1' in the frequent list")
+	}
+	if value := len(policy.ghosts); value > 2 {
+		t.Errorf("Expected at most '2' ghosts, got '%d'", value)
+	}
+}
+
+// Test Case 3: Ghosts age out after the default TTL
+func TestARCGhostExpiry(t *testing.T) {
+	cache := NewARCCache[string, string](1, 10*time.Millisecond, nil, quietListener[string]{}, 5*time.Second)
+	policy := cache.policy.(*arcPolicy[string, string])
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")       // Evicts key1
+	time.Sleep(20 * time.Millisecond) // Wait for the ghost to age out
+	cache.Put("key1", "value1")
+	if value := policy.target; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+	if item := cache.cache["key1"]; item.segment != recentSegment {
+		t.Errorf("Expected 'key1' in the recent list")
+	}
+}
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// listenerCall is one listener callback, captured so that it can run later on
+// a WithAsyncListener worker.
+type listenerCall[K comparable] struct {
+	ctx       context.Context
+	listener  CacheListener[K]
+	eventType EventType
+	key       K
+}
+
+// call invokes the callback for the event. The optional listener interfaces
+// are checked here, so a listener that does not implement one simply misses
+// its events.
+func (l listenerCall[K]) call() {
+	contextual, withContext := l.listener.(ContextListener[K])
+	switch l.eventType {
+	case EventHit:
+		if withContext {
+			contextual.OnHitContext(l.ctx, l.key)
+		} else {
+			l.listener.OnHit(l.key)
+		}
+	case EventMiss:
+		if withContext {
+			contextual.OnMissContext(l.ctx, l.key)
+		} else {
+			l.listener.OnMiss(l.key)
+		}
+	case EventLoad:
+		listener, ok := l.listener.(LoadListener[K])
+		switch {
+		case ok && withContext:
+			contextual.OnLoadContext(l.ctx, l.key)
+		case ok:
+			listener.OnLoad(l.key)
+		}
+	case EventEvict:
+		l.listener.OnEvict(l.key)
+	case EventExpire:
+		l.listener.OnExpire(l.key)
+	case EventRemove:
+		if listener, ok := l.listener.(RemovalListener[K]); ok {
+			listener.OnRemove(l.key)
+		}
+	case EventReject:
+		if listener, ok := l.listener.(RejectListener[K]); ok {
+			listener.OnReject(l.key)
+		}
+	case EventRefresh:
+		if listener, ok := l.listener.(RefreshListener[K]); ok {
+			listener.OnRefresh(l.key)
+		}
+	}
+}
+
+// asyncDispatcher runs listener callbacks on a fixed set of workers. Each key
+// hashes to one worker, whose queue is processed in order.
+type asyncDispatcher[K comparable] struct {
+	hasher func(K) uint64
+	queues []chan listenerCall[K]
+	done   sync.WaitGroup
+	// mutex keeps stop from closing the queues under a concurrent send.
+	mutex   sync.RWMutex
+	stopped bool
+}
+
+func newAsyncDispatcher[K comparable](workers, buffer int, hasher func(K) uint64) *asyncDispatcher[K] {
+	if hasher == nil {
+		hasher = defaultHasher[K]()
+	}
+	d := &asyncDispatcher[K]{hasher: hasher, queues: make([]chan listenerCall[K], workers)}
+	for i := range d.queues {
+		queue := make(chan listenerCall[K], buffer)
+		d.queues[i] = queue
+		d.done.Add(1)
+		go func() {
+			defer d.done.Done()
+			for call := range queue {
+				call.call()
+			}
+		}()
+	}
+	return d
+}
+
+// send queues call on its key's worker, blocking while that queue is full.
+// Once the dispatcher is stopped calls run on the caller's goroutine.
+func (d *asyncDispatcher[K]) send(call listenerCall[K]) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	if d.stopped {
+		call.call()
+		return
+	}
+	d.queues[d.hasher(call.key)%uint64(len(d.queues))] <- call
+}
+
+// stop waits for the workers to run every queued call.
+func (d *asyncDispatcher[K]) stop() {
+	d.mutex.Lock()
+	if d.stopped {
+		d.mutex.Unlock()
+		return
+	}
+	d.stopped = true
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.mutex.Unlock()
+	d.done.Wait()
+}
+
+// notify runs the listener callback for an event, on a worker when
+// WithAsyncListener is set and otherwise straight away.
+func (c *LRUCache[K, V]) notify(eventType EventType, key K) {
+	c.notifyContext(context.Background(), eventType, key)
+}
+
+// notifyContext is notify for an event caused by a read made with ctx, which
+// is passed on to a ContextListener.
+func (c *LRUCache[K, V]) notifyContext(ctx context.Context, eventType EventType, key K) {
+	call := listenerCall[K]{ctx: ctx, listener: c.cacheListener, eventType: eventType, key: key}
+	if c.listeners != nil {
+		c.listeners.send(call)
+		return
+	}
+	call.call()
+}
+
+// stopListeners delivers the callbacks still queued for WithAsyncListener
+// workers and stops them.
+func (c *LRUCache[K, V]) stopListeners() {
+	if c.listeners != nil {
+		c.listeners.stop()
+	}
+}
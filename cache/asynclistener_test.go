@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowListener records events after a delay, like a listener that writes to a
+// remote metrics system.
+type slowListener struct {
+	delay  time.Duration
+	mutex  sync.Mutex
+	events map[string][]string
+}
+
+func newSlowListener(delay time.Duration) *slowListener {
+	return &slowListener{delay: delay, events: make(map[string][]string)}
+}
+
+func (l *slowListener) record(key, event string) {
+	time.Sleep(l.delay)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.events[key] = append(l.events[key], event)
+}
+
+func (l *slowListener) OnHit(key string)    { l.record(key, "hit") }
+func (l *slowListener) OnMiss(key string)   { l.record(key, "miss") }
+func (l *slowListener) OnEvict(key string)  { l.record(key, "evict") }
+func (l *slowListener) OnExpire(key string) { l.record(key, "expire") }
+func (l *slowListener) OnRemove(key string) { l.record(key, "remove") }
+
+// Test Case 1: A slow listener does not slow the cache down
+func TestAsyncListenerOffCriticalPath(t *testing.T) {
+	listener := newSlowListener(5 * time.Millisecond)
+	cache := NewLRUCache[string, string](100, time.Minute, nil, listener, time.Minute,
+		WithAsyncListener[string, string](4, 1000))
+
+	cache.Put("key", "value")
+	start := time.Now()
+	for i := 0; i < 200; i++ {
+		cache.Get("key")
+	}
+	// Run synchronously the hits alone would take a second.
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected Gets not to wait for the listener, took %v", elapsed)
+	}
+
+	cache.Close()
+	if value := len(listener.events["key"]); value != 200 {
+		t.Errorf("Expected every hit to be delivered by Close, got '%d'", value)
+	}
+}
+
+// Test Case 2: Events for a key are delivered in order
+func TestAsyncListenerPerKeyOrder(t *testing.T) {
+	listener := newSlowListener(0)
+	cache := NewLRUCache[string, string](2, time.Minute, nil, listener, time.Minute,
+		WithAsyncListener[string, string](4, 16))
+
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprint("key", i)
+		cache.Get(key)
+		cache.Put(key, "value")
+		cache.Get(key)
+		cache.Remove(key)
+	}
+	cache.Close()
+
+	expected := []string{"miss", "hit", "remove"}
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprint("key", i)
+		events := listener.events[key]
+		if len(events) != len(expected) {
+			t.Fatalf("Expected %v for %s, got %v", expected, key, events)
+		}
+		for j := range expected {
+			if events[j] != expected[j] {
+				t.Errorf("Expected %v for %s, got %v", expected, key, events)
+				break
+			}
+		}
+	}
+
+	// After Close events are delivered synchronously.
+	cache.Get("late")
+	if value := listener.events["late"]; len(value) != 1 {
+		t.Errorf("Expected the miss to be delivered at once, got %v", value)
+	}
+}
+
+type traceKey struct{}
+
+// tracingListener records the trace ID its context-aware callbacks see.
+type tracingListener struct {
+	quietListener[string]
+	mutex  sync.Mutex
+	traces []string
+}
+
+func (l *tracingListener) record(event string, ctx context.Context, key string) {
+	trace, _ := ctx.Value(traceKey{}).(string)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.traces = append(l.traces, fmt.Sprintf("%s:%s:%s", event, key, trace))
+}
+
+func (l *tracingListener) OnLoad(string) {}
+
+func (l *tracingListener) OnHitContext(ctx context.Context, key string) { l.record("hit", ctx, key) }
+
+func (l *tracingListener) OnMissContext(ctx context.Context, key string) { l.record("miss", ctx, key) }
+
+func (l *tracingListener) OnLoadContext(ctx context.Context, key string) { l.record("load", ctx, key) }
+
+// Test Case 3: A ContextListener sees the context of the read, even async
+func TestContextListener(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		listener := &tracingListener{}
+		opts := []Option[string, string]{
+			WithListener[string, string](listener),
+			WithLoader[string, string](func(key string) (string, bool) { return key, key != "absent" }),
+		}
+		if async {
+			opts = append(opts, WithAsyncListener[string, string](2, 16))
+		}
+		cache, err := New(opts...)
+		if err != nil {
+			t.Fatalf("Expected no error, got '%v'", err)
+		}
+
+		ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+		cache.GetContext(ctx, "key")
+		cache.GetContext(ctx, "key")
+		cache.GetContext(ctx, "absent")
+		cache.Get("key")
+		cache.Close() // Delivers the queued callbacks.
+
+		slices.Sort(listener.traces)
+		expected := []string{"hit:key:", "hit:key:trace-1", "load:key:trace-1", "miss:absent:trace-1"}
+		if !slices.Equal(listener.traces, expected) {
+			t.Errorf("Expected %v, got %v (async %v)", expected, listener.traces, async)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package cache
+
+import "time"
+
+// AutoResize configures the sizing controller enabled by WithAutoResize. Zero
+// fields other than the capacity bounds take their defaults.
+type AutoResize struct {
+	// MinCapacity and MaxCapacity bound the capacity the controller picks.
+	MinCapacity int
+	MaxCapacity int
+	// Interval is how often the controller runs; the hit ratio is measured
+	// over the requests made since the previous run. The default is a minute.
+	Interval time.Duration
+	// GrowBelow is the hit ratio under which the capacity grows. The default
+	// is 0.8.
+	GrowBelow float64
+	// ShrinkAbove is the hit ratio over which the capacity shrinks, since the
+	// cache is then larger than its working set needs. The default is 0.99.
+	ShrinkAbove float64
+	// Step is the fraction of the current capacity added or removed by each
+	// decision, at least one entry. The default is 0.25.
+	Step float64
+}
+
+// ResizeDecision records a capacity change made by the sizing controller.
+type ResizeDecision struct {
+	At       time.Time
+	HitRatio float64
+	From     int
+	To       int
+}
+
+// maxResizeDecisions bounds the history returned by ResizeDecisions.
+const maxResizeDecisions = 16
+
+func (a *AutoResize) setDefaults() {
+	if a.Interval <= 0 {
+		a.Interval = time.Minute
+	}
+	if a.GrowBelow == 0 {
+		a.GrowBelow = 0.8
+	}
+	if a.ShrinkAbove == 0 {
+		a.ShrinkAbove = 0.99
+	}
+	if a.Step <= 0 {
+		a.Step = 0.25
+	}
+	a.MinCapacity = max(a.MinCapacity, 1)
+}
+
+// adjustCapacity is one run of the sizing controller. It does not grow the
+// cache while a weight or memory bound is at least 90% used, since more
+// entries would not fit anyway.
+func (c *LRUCache[K, V]) adjustCapacity() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// The counters are read under the lock so that ResetStats cannot zero
+	// them between the read and the baseline update.
+	hits, misses := c.stats.hits.Load(), c.stats.misses.Load()
+	windowHits, windowMisses := hits-c.resizeWindow.hits, misses-c.resizeWindow.misses
+	c.resizeWindow.hits, c.resizeWindow.misses = hits, misses
+	if windowHits+windowMisses == 0 {
+		return
+	}
+	ratio := float64(windowHits) / float64(windowHits+windowMisses)
+
+	config := c.options.autoResize
+	step := max(int(float64(c.capacity)*config.Step), 1)
+	capacity := c.capacity
+	switch {
+	case ratio < config.GrowBelow && !c.nearWeightBound():
+		capacity = min(c.capacity+step, config.MaxCapacity)
+	case ratio > config.ShrinkAbove:
+		capacity = max(c.capacity-step, config.MinCapacity)
+	}
+	if capacity == c.capacity {
+		return
+	}
+
+	if len(c.resizeDecisions) == maxResizeDecisions {
+		c.resizeDecisions = c.resizeDecisions[1:]
+	}
+	c.resizeDecisions = append(c.resizeDecisions, ResizeDecision{At: c.now(), HitRatio: ratio, From: c.capacity, To: capacity})
+	c.resize(capacity)
+}
+
+func (c *LRUCache[K, V]) nearWeightBound() bool {
+	return c.options.weigher != nil && c.weight*10 >= c.options.maxWeight*9
+}
+
+// ResizeDecisions returns the most recent capacity changes made by the sizing
+// controller, oldest first.
+func (c *LRUCache[K, V]) ResizeDecisions() []ResizeDecision {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return append([]ResizeDecision(nil), c.resizeDecisions...)
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Test Case 1: A low hit ratio grows the cache and a near perfect one shrinks it
+func TestAutoResize(t *testing.T) {
+	backingStore := func(key string) (string, bool) { return key, true }
+	cache := NewLRUCache[string, string](50, time.Minute, backingStore, quietListener[string]{}, time.Minute,
+		WithAutoResize[string, string](AutoResize{MinCapacity: 20, MaxCapacity: 200, Interval: time.Hour}))
+	defer cache.Close()
+
+	// Cycling over 150 keys thrashes a 50 entry LRU cache.
+	for round := 0; round < 6; round++ {
+		for i := 0; i < 300; i++ {
+			cache.Get(fmt.Sprint("key", i%150))
+		}
+		cache.adjustCapacity()
+	}
+	grown := cache.Capacity()
+	if grown <= 50 || grown > 200 {
+		t.Errorf("Expected the capacity to grow from 50, got '%d'", grown)
+	}
+
+	// A handful of hot keys always hits.
+	for round := 0; round < 10; round++ {
+		for i := 0; i < 300; i++ {
+			cache.Get(fmt.Sprint("key", i%5))
+		}
+		cache.adjustCapacity()
+	}
+	if value := cache.Capacity(); value >= grown || value < 20 {
+		t.Errorf("Expected the capacity to shrink below '%d', got '%d'", grown, value)
+	}
+	if value := cache.Len(); value > cache.Capacity() {
+		t.Errorf("Expected at most '%d' entries, got '%d'", cache.Capacity(), value)
+	}
+
+	decisions := cache.ResizeDecisions()
+	if len(decisions) == 0 || decisions[0].From != 50 || decisions[0].To <= 50 {
+		t.Errorf("Expected the first decision to grow from 50, got %+v", decisions)
+	}
+	if last := decisions[len(decisions)-1]; last.To >= last.From || last.HitRatio < 0.99 {
+		t.Errorf("Expected the last decision to shrink on a high hit ratio, got %+v", last)
+	}
+	if value := cache.Stats().Capacity; value != cache.Capacity() {
+		t.Errorf("Expected '%d', got '%d'", cache.Capacity(), value)
+	}
+}
+
+// Test Case 2: An idle window changes nothing
+func TestAutoResizeIdle(t *testing.T) {
+	cache := NewLRUCache[string, string](500, time.Minute, nil, quietListener[string]{}, time.Minute,
+		WithAutoResize[string, string](AutoResize{MinCapacity: 10, MaxCapacity: 100}))
+	defer cache.Close()
+
+	if value := cache.Capacity(); value != 100 {
+		t.Errorf("Expected the capacity to be clamped to '100', got '%d'", value)
+	}
+	cache.adjustCapacity()
+	if value := len(cache.ResizeDecisions()); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 3: The policy and admitter are sized for the clamped capacity
+func TestAutoResizeClampSizesPolicy(t *testing.T) {
+	cache := NewLRUCache[string, string](500, time.Minute, nil, quietListener[string]{}, time.Minute,
+		WithPolicy[string, string](SLRU),
+		WithAdmission[string, string](TinyLFU),
+		WithAutoResize[string, string](AutoResize{MinCapacity: 10, MaxCapacity: 100}))
+	defer cache.Close()
+
+	policy := cache.policy.(*slruPolicy[string, string])
+	if expected := newSLRUPolicy[string, string](100, cache.options.probationRatio); policy.protectedCapacity != expected.protectedCapacity {
+		t.Errorf("Expected '%d' protected entries, got '%d'", expected.protectedCapacity, policy.protectedCapacity)
+	}
+	if expected := newCountMinSketch(100); cache.admitter.sketch.mask != expected.mask {
+		t.Errorf("Expected a sketch mask of '%d', got '%d'", expected.mask, cache.admitter.sketch.mask)
+	}
+}
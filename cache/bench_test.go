@@ -0,0 +1,269 @@
+package cache
+
+import (
+	"math/rand"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vivekkothari/in-memory-cache/cache/testutil/clocktest"
+)
+
+// The benchmarks go through the exported API, so that they stay comparable
+// across internal redesigns.
+
+const benchCapacity = 10_000
+
+func benchKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+func newBenchCache(capacity int, backingStore func(string) (string, bool)) *LRUCache[string, string] {
+	return NewLRUCache[string, string](capacity, time.Hour, backingStore, quietListener[string]{}, time.Hour)
+}
+
+func BenchmarkGetHit(b *testing.B) {
+	keys := benchKeys(benchCapacity)
+	cache := newBenchCache(benchCapacity, nil)
+	defer cache.Close()
+	for _, key := range keys {
+		cache.Put(key, key)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Get(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkGetMissWithLoader(b *testing.B) {
+	keys := benchKeys(benchCapacity * 10)
+	cache := newBenchCache(benchCapacity, func(key string) (string, bool) { return key, true })
+	defer cache.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Get(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkPutInsert(b *testing.B) {
+	keys := benchKeys(benchCapacity * 10)
+	cache := newBenchCache(benchCapacity, nil)
+	defer cache.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Put(keys[i%len(keys)], "value")
+	}
+}
+
+func BenchmarkPutUpdate(b *testing.B) {
+	keys := benchKeys(benchCapacity)
+	cache := newBenchCache(benchCapacity, nil)
+	defer cache.Close()
+	for _, key := range keys {
+		cache.Put(key, key)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Put(keys[i%len(keys)], "value")
+	}
+}
+
+func BenchmarkPutRemoveChurn(b *testing.B) {
+	keys := benchKeys(benchCapacity)
+	cache := newBenchCache(benchCapacity, nil)
+	defer cache.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key := keys[i%len(keys)]
+		cache.Put(key, "value")
+		cache.Remove(key)
+	}
+}
+
+func benchmarkMixed(b *testing.B, cache Cache[string, string]) {
+	keys := benchKeys(benchCapacity * 2)
+	for _, key := range keys[:benchCapacity] {
+		cache.Put(key, key)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			key := keys[r.Intn(len(keys))]
+			if r.Intn(10) == 0 {
+				cache.Put(key, key)
+			} else {
+				cache.Get(key)
+			}
+		}
+	})
+}
+
+func BenchmarkMixedParallel(b *testing.B) {
+	b.Run("LRU", func(b *testing.B) {
+		cache := newBenchCache(benchCapacity, nil)
+		defer cache.Close()
+		benchmarkMixed(b, cache)
+	})
+	b.Run("Sharded", func(b *testing.B) {
+		cache := NewShardedLRUCache[string, string](16, benchCapacity, time.Hour, nil, quietListener[string]{}, time.Hour)
+		defer cache.Close()
+		benchmarkMixed(b, cache)
+	})
+}
+
+func BenchmarkZipfian(b *testing.B) {
+	keys := benchKeys(benchCapacity * 10)
+	cache := newBenchCache(benchCapacity, func(key string) (string, bool) { return key, true })
+	defer cache.Close()
+	zipf := rand.NewZipf(rand.New(rand.NewSource(1)), 1.1, 1, uint64(len(keys)-1))
+	trace := make([]string, 1<<16)
+	for i := range trace {
+		trace[i] = keys[zipf.Uint64()]
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Get(trace[i%len(trace)])
+	}
+	stats := cache.Stats()
+	b.ReportMetric(float64(stats.Hits)/float64(stats.Hits+stats.Misses), "hit-ratio")
+}
+
+// BenchmarkCleanupSweep times the janitor sweeping a cache whose entries have
+// all expired, ticking it with a fake clock. One long-lived entry keeps the
+// cache from going idle, which would stop the ticks.
+func BenchmarkCleanupSweep(b *testing.B) {
+	for _, size := range []int{1_000, 10_000, 100_000} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			keys := benchKeys(size)
+			clock := clocktest.New(time.Now())
+			cache := NewLRUCache[string, string](size+1, time.Second, nil, quietListener[string]{}, time.Minute,
+				WithClock[string, string](clock))
+			defer cache.Close()
+			cache.Put("resident", "value", 365*24*time.Hour)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				for _, key := range keys {
+					cache.Put(key, key)
+				}
+				b.StartTimer()
+				clock.Advance(time.Minute)
+				for cache.Len() > 1 {
+					runtime.Gosched()
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetParallel(b *testing.B) {
+	for name, opts := range map[string][]Option[string, string]{
+		"StrictLRU":      nil,
+		"ApproximateLRU": {WithApproximateLRU[string, string](8)},
+		"SecondChance":   {WithPolicy[string, string](SecondChance)},
+		"Random":         {WithPolicy[string, string](Random)},
+		"SampledLRU":     {WithPolicy[string, string](SampledLRU)},
+	} {
+		b.Run(name, func(b *testing.B) {
+			keys := benchKeys(benchCapacity)
+			cache := NewLRUCache[string, string](benchCapacity, time.Hour, nil, quietListener[string]{}, time.Hour, opts...)
+			defer cache.Close()
+			for _, key := range keys {
+				cache.Put(key, key)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				r := rand.New(rand.NewSource(rand.Int63()))
+				for pb.Next() {
+					cache.Get(keys[r.Intn(len(keys))])
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkCompression measures the CPU cost of WithCompression on repetitive
+// JSON values and reports the ratio of original to stored bytes as the
+// capacity gain under a weight or memory bound.
+func BenchmarkCompression(b *testing.B) {
+	value := []byte(strings.Repeat(`{"id":12345,"name":"example","tags":["a","b"]},`, 64))
+	keys := benchKeys(1000)
+	for _, bc := range []struct {
+		name string
+		opts []Option[string, []byte]
+	}{
+		{"Off", nil},
+		{"On", []Option[string, []byte]{WithCompression[string, []byte](256)}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			opts := append(bc.opts, WithMaximumWeight[string, []byte](1<<40, func(key string, value []byte) int64 {
+				return int64(len(value))
+			}))
+			cache := NewLRUCache[string, []byte](len(keys), time.Hour, nil, quietListener[string]{}, time.Hour, opts...)
+			defer cache.Close()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				key := keys[i%len(keys)]
+				cache.Put(key, value)
+				cache.Get(key)
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(len(value)*cache.Len())/float64(cache.Weight()), "gain")
+		})
+	}
+}
+
+// BenchmarkConcurrentDistinctLoads loads distinct keys from a slow backing
+// store in parallel, with and without the shared loads of WithLockStripes,
+// which should cost distinct keys nothing.
+func BenchmarkConcurrentDistinctLoads(b *testing.B) {
+	for _, stripes := range []int{0, 256} {
+		b.Run("stripes="+strconv.Itoa(stripes), func(b *testing.B) {
+			loader := func(key string) (string, bool) {
+				time.Sleep(50 * time.Microsecond)
+				return key, true
+			}
+			cache := NewLRUCache[string, string](0, time.Hour, loader, quietListener[string]{}, time.Hour,
+				WithLockStripes[string, string](stripes))
+			defer cache.Close()
+			var next atomic.Int64
+
+			// The loads mostly sleep, so overlap them even on a single CPU.
+			b.SetParallelism(16)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					cache.Get("key" + strconv.FormatInt(next.Add(1), 10))
+				}
+			})
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package cache
 
 import (
-	"container/list"
+	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,6 +18,47 @@ type CacheListener[K comparable] interface {
 	OnExpire(key K)
 }
 
+// RemovalListener can be implemented by a CacheListener that also wants to be
+// told when an entry is explicitly removed, as opposed to evicted or expired.
+type RemovalListener[K comparable] interface {
+	OnRemove(key K)
+}
+
+// LoadListener can be implemented by a CacheListener that wants misses served
+// by the backing store reported separately. Implementing it changes when OnMiss
+// fires: instead of on every lookup that finds nothing in the cache, OnMiss
+// fires only once the backing store has also failed to produce the key, and
+// OnLoad fires when the backing store returns it. Stats count misses the same
+// way either way.
+type LoadListener[K comparable] interface {
+	OnLoad(key K)
+}
+
+// RejectListener can be implemented by a CacheListener that also wants to be
+// told when the admission filter turns a new key away.
+type RejectListener[K comparable] interface {
+	OnReject(key K)
+}
+
+// RefreshListener can be implemented by a CacheListener that also wants to be
+// told when an entry is reloaded by Refresh or WithRefreshAhead, as opposed to
+// loaded after a miss.
+type RefreshListener[K comparable] interface {
+	OnRefresh(key K)
+}
+
+// ContextListener can be implemented by a CacheListener that wants the
+// context of the read behind a hit, miss or load, for example to attach the
+// event to the caller's trace span. Its methods are called instead of OnHit,
+// OnMiss and OnLoad; OnLoadContext, like OnLoad, only for a listener that is
+// also a LoadListener. Reads made through GetContext pass on their context;
+// every other read passes context.Background().
+type ContextListener[K comparable] interface {
+	OnHitContext(ctx context.Context, key K)
+	OnMissContext(ctx context.Context, key K)
+	OnLoadContext(ctx context.Context, key K)
+}
+
 type NoOpCacheListener[K comparable] struct {
 }
 
@@ -33,34 +78,250 @@ func (c *NoOpCacheListener[K]) OnExpire(key K) {
 	fmt.Println(key, "Expired")
 }
 
+func (c *NoOpCacheListener[K]) OnRemove(key K) {
+	fmt.Println(key, "Removed")
+}
+
+// quietListener discards every event and is safe for concurrent use.
+type quietListener[K comparable] struct{}
+
+func (quietListener[K]) OnHit(K)    {}
+func (quietListener[K]) OnMiss(K)   {}
+func (quietListener[K]) OnEvict(K)  {}
+func (quietListener[K]) OnExpire(K) {}
+
 type Cache[K comparable, V any] interface {
 	Put(key K, value V, ttl ...time.Duration)
 	Get(key K) V
+	// GetOk is like Get but also reports whether the value was found, either in
+	// the cache or in its backing store.
+	GetOk(key K) (V, bool)
 	Remove(key K)
 	//Called when your server stops
 	Close()
 }
 
+// CleanupStrategy selects how the background goroutine finds expired entries.
+type CleanupStrategy int
+
+const (
+	// FullScanCleanup checks every entry on each tick while holding the lock.
+	FullScanCleanup CleanupStrategy = iota
+	// SampledCleanup checks a small random sample of entries per round, in the
+	// style of Redis' active expiry, and only keeps going while the sample shows
+	// a high proportion of expired entries. Combined with the lazy expiry done by
+	// Get this bounds memory without long pauses on very large caches.
+	SampledCleanup
+)
+
+// CleanupStats describes the work done by the most recent cleanup cycle.
+type CleanupStats struct {
+	Sampled int
+	Expired int
+	Rounds  int
+}
+
 type CacheItem[K comparable, V any] struct {
-	key       K
-	value     V
-	timestamp time.Time
+	key   K
+	value V
+	// timestamp is the time of the last write or access in Unix nanoseconds.
+	// It is atomic so that hits served under the read lock can refresh it.
+	timestamp atomic.Int64
 	expiry    time.Duration
+	prev      *CacheItem[K, V]
+	next      *CacheItem[K, V]
+	bucket    *lfuBucket[K, V]
+	segment   uint8
+	// index is the item's position in the Random policy's slice.
+	index  int
+	weight int64
+	tags   []string
+	pinned bool
+	// priority is set by PutWithPriority; lower priorities are evicted first.
+	priority int
+	// noExpiry exempts the item from its TTL.
+	noExpiry bool
+	// compressed marks a value stored gzipped by WithCompression.
+	compressed bool
+	// referenced is the SecondChance policy's reference bit.
+	referenced atomic.Bool
+	// uses counts the item's uses for the Scored policy.
+	uses atomic.Uint64
+}
+
+func (i *CacheItem[K, V]) lastAccess() time.Time {
+	return time.Unix(0, i.timestamp.Load())
 }
 
+func (i *CacheItem[K, V]) touch(now time.Time) {
+	i.timestamp.Store(now.UnixNano())
+}
+
+// expiredAt reports whether the item's TTL has run out by now. A TTL of zero
+// never runs out.
+func (i *CacheItem[K, V]) expiredAt(now time.Time) bool {
+	return !i.noExpiry && i.expiry != 0 && now.Sub(i.lastAccess()) > i.expiry
+}
+
+// expiresAt returns when the item is due to expire, or the zero time if its
+// TTL is zero.
+func (i *CacheItem[K, V]) expiresAt() time.Time {
+	if i.expiry == 0 {
+		return time.Time{}
+	}
+	return i.lastAccess().Add(i.expiry)
+}
+
+// remaining returns how long the item has left to live at now, or zero if its
+// TTL is zero.
+func (i *CacheItem[K, V]) remaining(now time.Time) time.Duration {
+	if i.expiry == 0 {
+		return 0
+	}
+	return i.expiry - now.Sub(i.lastAccess())
+}
+
+// LRUCache is the package's cache implementation. It evicts the least recently
+// used entry by default; other eviction policies can be selected with
+// WithPolicy or the dedicated constructors such as NewLFUCache.
 type LRUCache[K comparable, V any] struct {
 	capacity        int
-	cache           map[K]*list.Element
-	order           *list.List
+	cache           map[K]*CacheItem[K, V]
+	policy          evictionPolicy[K, V]
+	sharedHits      bool
+	admitter        *admitter[K]
+	weight          int64
+	tags            map[string]map[K]struct{}
+	pinned          int
+	priorities      map[int]int
+	resizeWindow    struct{ hits, misses uint64 }
+	resizeDecisions []ResizeDecision
 	mutex           sync.RWMutex
 	defaultTTL      time.Duration
 	backingStore    func(K) (V, bool)
 	cacheListener   CacheListener[K]
 	cleanupInterval time.Duration
 	stopCleanup     chan struct{}
+	cleanupDone     chan struct{}
+	evictNow        chan struct{}
+	closeOnce       sync.Once
+	closed          atomic.Bool
+	itemPool        sync.Pool
+	options         options[K, V]
+	negative        *LRUCache[K, struct{}]
+	cleanupStats    CleanupStats
+	stats           counters
+
+	// wake resumes cleanup paused on an empty cache; idle records the pause.
+	wake chan struct{}
+	idle bool
+	// loads holds a token for each backing-store call in flight under
+	// WithMaxConcurrentLoads.
+	loads chan struct{}
+	// subscribers is replaced rather than modified, so that events can be
+	// published without a lock; subscribersMutex serializes the replacements.
+	subscribers      atomic.Pointer[[]*subscription[K]]
+	subscribersMutex sync.Mutex
+	// listeners runs listener callbacks under WithAsyncListener.
+	listeners *asyncDispatcher[K]
+	// refresher runs the reloads of WithRefreshAhead.
+	refresher *refresher[K]
+	// keyLocks are the locks of LockKey, created on first use.
+	keyLocks     *lockStripes[K]
+	keyLocksOnce sync.Once
+	// holds maps keys removed under WithRemoveHold to the end of their hold,
+	// in Unix nanoseconds.
+	holds map[K]int64
+	// spilled maps the keys evicted to the WithSpill store to what is needed
+	// to bring them back.
+	spilled map[K]spilledEntry
+	// evictions feeds EvictionRate.
+	evictions evictionWindow
+	// fills tracks the backing-store loads in flight, by key.
+	fills map[K]*fill[V]
+	// callNegative remembers the keys GetOrLoadNeg loaders did not find,
+	// apart from negative so that a key one loader misses is not hidden
+	// from the others.
+	callNegative *LRUCache[K, struct{}]
 }
 
-func NewLRUCache[K comparable, V any](capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration) *LRUCache[K, V] {
+// NewLRUCache returns a cache holding up to capacity entries. A capacity of
+// zero or less makes the cache unbounded, so that entries only leave it by
+// expiry or removal; its Stats then report a capacity of zero. Entries live
+// for defaultTTL unless Put is given another TTL, and a TTL of zero, whether
+// the default or per key, means the entry never expires. It panics on a
+// configuration that cannot work, such as a non-positive cleanup interval;
+// NewLRUCacheChecked reports these and other mistakes as errors instead.
+//
+// Deprecated: Use New, which takes every setting as an option.
+func NewLRUCache[K comparable, V any](capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration, opts ...Option[K, V]) *LRUCache[K, V] {
+	cache := newLRUCache(capacity, defaultTTL, backingStore, cacheListener, cleanupInterval, opts...)
+	cache.seed(cache.options.initialEntries)
+	if path := cache.options.persistPath; path != "" {
+		if err := cache.loadFile(path); err != nil {
+			cache.onError(fmt.Errorf("cache: loading from %s: %w", path, err))
+		}
+	}
+	cache.start()
+	return cache
+}
+
+// start launches the cache's own cleanup goroutine, which caches owned by a
+// ShardedLRUCache do without.
+func (c *LRUCache[K, V]) start() {
+	c.cleanupDone = make(chan struct{})
+	c.wake = make(chan struct{}, 1)
+	if c.options.lowWatermark > 0 && c.options.watermarkInBackground {
+		c.evictNow = make(chan struct{}, 1)
+	}
+	// Waiting for the tickers to exist means that time a fake clock is
+	// advanced by straight after construction is not missed.
+	ready := make(chan struct{})
+	go c.startCleanup(ready)
+	<-ready
+}
+
+// NewLFUCache takes the same arguments as NewLRUCache and returns a cache that
+// evicts the least frequently used entry instead, which protects a small set
+// of popular keys from being flushed out by bursts of one-off keys. TTLs, the
+// backing store and the listener behave exactly as they do for LRU.
+func NewLFUCache[K comparable, V any](capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration, opts ...Option[K, V]) *LRUCache[K, V] {
+	opts = append([]Option[K, V]{WithPolicy[K, V](LFU)}, opts...)
+	return NewLRUCache(capacity, defaultTTL, backingStore, cacheListener, cleanupInterval, opts...)
+}
+
+// NewARCCache takes the same arguments as NewLRUCache and returns a cache that
+// uses the adaptive replacement policy, which balances recency against
+// frequency on its own as the workload shifts. Evicted keys are remembered
+// without their values for up to defaultTTL; real entries keep their TTLs and
+// listener events exactly as they do for LRU.
+func NewARCCache[K comparable, V any](capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration, opts ...Option[K, V]) *LRUCache[K, V] {
+	opts = append([]Option[K, V]{WithPolicy[K, V](ARC)}, opts...)
+	return NewLRUCache(capacity, defaultTTL, backingStore, cacheListener, cleanupInterval, opts...)
+}
+
+// NewSampledLRUCache takes the same arguments as NewLRUCache and returns a
+// cache that approximates LRU by sampling: on overflow it evicts the least
+// recently used of a few randomly chosen entries. It avoids reordering a list
+// on every hit, which pays off for very large caches; the sample size is set
+// with WithEvictionSamples.
+func NewSampledLRUCache[K comparable, V any](capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration, opts ...Option[K, V]) *LRUCache[K, V] {
+	opts = append([]Option[K, V]{WithPolicy[K, V](SampledLRU)}, opts...)
+	return NewLRUCache(capacity, defaultTTL, backingStore, cacheListener, cleanupInterval, opts...)
+}
+
+// NewFIFOCache takes the same arguments as NewLRUCache and returns a cache that
+// evicts entries strictly in insertion order. Neither reads nor overwrites
+// change an entry's position, so hits are served under the read lock and the
+// listener's OnHit may be called concurrently.
+func NewFIFOCache[K comparable, V any](capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration, opts ...Option[K, V]) *LRUCache[K, V] {
+	opts = append([]Option[K, V]{WithPolicy[K, V](FIFO)}, opts...)
+	return NewLRUCache(capacity, defaultTTL, backingStore, cacheListener, cleanupInterval, opts...)
+}
+
+// newLRUCache builds a cache without starting its cleanup goroutine, for
+// callers such as ShardedLRUCache that drive cleanup themselves.
+func newLRUCache[K comparable, V any](capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration, opts ...Option[K, V]) *LRUCache[K, V] {
 	var listener CacheListener[K]
 	if cacheListener == nil {
 		listener = &NoOpCacheListener[K]{}
@@ -76,135 +337,1309 @@ func NewLRUCache[K comparable, V any](capacity int, defaultTTL time.Duration, ba
 	} else {
 		refillStore = backingStore
 	}
+	capacity = max(capacity, 0)
 	cache := &LRUCache[K, V]{
 		capacity:        capacity,
-		cache:           make(map[K]*list.Element),
-		order:           list.New(),
+		cache:           make(map[K]*CacheItem[K, V]),
 		defaultTTL:      defaultTTL,
 		backingStore:    refillStore,
 		cacheListener:   listener,
 		cleanupInterval: cleanupInterval,
 		stopCleanup:     make(chan struct{}),
+		options:         defaultOptions[K, V](),
+	}
+	cache.itemPool.New = func() any {
+		return new(CacheItem[K, V])
+	}
+	for _, opt := range opts {
+		opt(&cache.options)
+	}
+	if err := cache.options.validateUsable(cleanupInterval); err != nil {
+		panic(err)
+	}
+	if cache.options.autoResize != nil {
+		cache.capacity = min(max(cache.capacity, cache.options.autoResize.MinCapacity), cache.options.autoResize.MaxCapacity)
+	}
+	if cache.options.evictionPolicy != nil {
+		cache.policy = &customPolicy[K, V]{policy: cache.options.evictionPolicy(), items: &cache.cache}
+	} else {
+		cache.policy = newEvictionPolicy(&cache.options, cache.capacity, defaultTTL)
+	}
+	if cache.options.admission == TinyLFU {
+		cache.admitter = newAdmitter(cache.capacity, cache.options.hasher)
+	} else {
+		cache.sharedHits = cache.policy.sharedHits() || cache.options.promoteOneIn > 1
+	}
+	cache.evictions = newEvictionWindow(cache.options.evictionRateWindow)
+	if cache.options.asyncListener {
+		cache.listeners = newAsyncDispatcher[K](cache.options.listenerWorkers, cache.options.listenerBuffer, cache.options.hasher)
+	}
+	if cache.options.refreshWindow > 0 {
+		cache.refresher = newRefresher(cache.options.refreshWorkers, cache.options.refreshQueue, cache.refresh)
+	}
+	if cache.options.invalidationBus != nil {
+		cache.options.invalidationBus.Subscribe(cache.invalidate)
+	}
+	if cache.options.maxConcurrentLoads > 0 {
+		cache.loads = make(chan struct{}, cache.options.maxConcurrentLoads)
+	}
+	if cache.options.negativeCapacity > 0 {
+		cache.negative = newLRUCache[K, struct{}](cache.options.negativeCapacity, cache.options.negativeTTL, nil, quietListener[K]{}, cleanupInterval, WithClock[K, struct{}](cache.options.clock))
+		cache.callNegative = newLRUCache[K, struct{}](cache.options.negativeCapacity, cache.options.negativeTTL, nil, quietListener[K]{}, cleanupInterval, WithClock[K, struct{}](cache.options.clock))
 	}
-	go cache.startCleanup()
 	return cache
 }
 
-func (c *LRUCache[K, V]) startCleanup() {
-	ticker := time.NewTicker(c.cleanupInterval)
-	defer ticker.Stop()
+func (c *LRUCache[K, V]) startCleanup(ready chan<- struct{}) {
+	defer close(c.cleanupDone)
+	clock := c.options.clock
+	cleanups, stopCleanups := clock.NewTicker(c.cleanupInterval)
+	defer func() { stopCleanups() }()
+
+	var snapshots <-chan time.Time
+	if c.options.snapshotWriter != nil || c.options.snapshotPath != "" {
+		var stop func()
+		snapshots, stop = clock.NewTicker(c.options.snapshotInterval)
+		defer stop()
+	}
+
+	var resizes <-chan time.Time
+	if c.options.autoResize != nil {
+		var stop func()
+		resizes, stop = clock.NewTicker(c.options.autoResize.Interval)
+		defer stop()
+	}
+	close(ready)
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-cleanups:
 			c.cleanupExpiredEntries()
+			c.relieveMemoryPressure()
+			if c.pauseIfEmpty() {
+				stopCleanups()
+				cleanups, stopCleanups = nil, func() {}
+			}
+		case <-c.wake:
+			stopCleanups()
+			cleanups, stopCleanups = clock.NewTicker(c.cleanupInterval)
+		case <-snapshots:
+			c.periodicSnapshot()
+		case <-resizes:
+			c.adjustCapacity()
+		case <-c.evictNow:
+			c.mutex.Lock()
+			c.evictDownTo(c.lowWatermark(), nil)
+			c.mutex.Unlock()
 		case <-c.stopCleanup:
 			return
 		}
 	}
 }
 
+// pauseIfEmpty reports whether the cache is empty, in which case cleanup
+// sleeps until put wakes it, sparing idle caches the periodic wakeups.
+func (c *LRUCache[K, V]) pauseIfEmpty() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.idle = len(c.cache) == 0
+	return c.idle
+}
+
 func (c *LRUCache[K, V]) cleanupExpiredEntries() {
+	c.stats.cleanups.Add(1)
+	if c.options.cleanupStrategy == SampledCleanup {
+		c.cleanupSampledEntries()
+		return
+	}
+
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	now := time.Now()
-	for key, elem := range c.cache {
-		item := elem.Value.(*CacheItem[K, V])
-		fmt.Println("checking key", key)
-		if now.Sub(item.timestamp) > item.expiry {
-			fmt.Println("Trying to cleanup", key)
-			c.cacheListener.OnExpire(key)
-			c.order.Remove(elem)
-			delete(c.cache, key)
+	c.releaseHolds()
+	c.dropAllSpilled(true)
+	stats := CleanupStats{Sampled: len(c.cache), Rounds: 1}
+	now := c.now()
+	for _, item := range c.cache {
+		if item.expiredAt(now) {
+			c.removeItem(item)
+			c.onExpire(item)
+			c.releaseItem(item)
+			stats.Expired++
+		}
+	}
+	c.cleanupStats = stats
+}
+
+// cleanupSampledEntries relies on Go's randomised map iteration order to pick
+// the sample. The lock is released between rounds so that a long cycle never
+// blocks other callers for more than one sample.
+func (c *LRUCache[K, V]) cleanupSampledEntries() {
+	var stats CleanupStats
+	for stats.Rounds < c.options.maxSampleRounds {
+		sampled, expired := c.expireSample(c.options.sampleSize)
+		stats.Sampled += sampled
+		stats.Expired += expired
+		stats.Rounds++
+		if sampled == 0 || expired*4 <= sampled {
+			break
 		}
 	}
+
+	c.mutex.Lock()
+	c.releaseHolds()
+	c.dropAllSpilled(true)
+	c.cleanupStats = stats
+	c.mutex.Unlock()
 }
 
-var closeOnce sync.Once
+func (c *LRUCache[K, V]) expireSample(size int) (sampled, expired int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	for _, item := range c.cache {
+		if sampled >= size {
+			break
+		}
+		sampled++
+		if item.expiredAt(now) {
+			c.removeItem(item)
+			c.onExpire(item)
+			c.releaseItem(item)
+			expired++
+		}
+	}
+	return sampled, expired
+}
+
+// LastCleanupStats reports what the most recent background cleanup cycle did,
+// which is useful for tuning the cleanup interval and sampling parameters.
+func (c *LRUCache[K, V]) LastCleanupStats() CleanupStats {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.cleanupStats
+}
 
 func (c *LRUCache[K, V]) Close() {
-	closeOnce.Do(func() {
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
 		close(c.stopCleanup)
+		if c.cleanupDone != nil {
+			<-c.cleanupDone
+		}
+		c.stopRefresher()
+		if c.options.snapshotWriter != nil || c.options.snapshotPath != "" {
+			c.periodicSnapshot()
+		}
+		if path := c.options.persistPath; path != "" {
+			if err := c.saveFile(path); err != nil {
+				c.onError(fmt.Errorf("cache: saving to %s: %w", path, err))
+			}
+		}
+		c.closeSubscribers()
+		c.stopListeners()
 	})
 }
 
 func (c *LRUCache[K, V]) Put(key K, value V, ttl ...time.Duration) {
+	key = c.normalize(key)
+	defer c.publishInvalidation(true, key)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.put(key, value, c.expiryFor(ttl))
+}
+
+// PutE stores value under key like Put, but returns ErrValueTooLarge, leaving
+// the cache unchanged, when the value alone weighs more than the maximum
+// weight and so could never be stored. Put instead reports such a value as a
+// rejection, and drops the entry it would have replaced. After Close it
+// returns ErrClosed.
+func (c *LRUCache[K, V]) PutE(key K, value V, ttl ...time.Duration) error {
+	if c.closed.Load() {
+		return ErrClosed
+	}
+	key = c.normalize(key)
+	c.mutex.Lock()
+	if c.tooHeavy(key, value) {
+		c.mutex.Unlock()
+		return ErrValueTooLarge
+	}
+	c.put(key, value, c.expiryFor(ttl))
+	c.mutex.Unlock()
+	c.publishInvalidation(true, key)
+	return nil
+}
+
+// Set stores value under key like Put, but an existing entry keeps its place
+// in the eviction order, so writes do not count as uses. A new key is inserted
+// exactly as Put would insert it.
+func (c *LRUCache[K, V]) Set(key K, value V, ttl ...time.Duration) {
+	key = c.normalize(key)
+	defer c.publishInvalidation(true, key)
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	expiry := c.defaultTTL
+	if item, found := c.cache[key]; found {
+		if c.setValue(item, value) {
+			item.touch(c.now())
+			item.expiry = c.expiryFor(ttl)
+		}
+		return
+	}
+	c.put(key, value, c.expiryFor(ttl))
+}
+
+func (c *LRUCache[K, V]) expiryFor(ttl []time.Duration) time.Duration {
 	if len(ttl) > 0 {
-		expiry = ttl[0]
+		return ttl[0]
+	}
+	return c.defaultTTL
+}
+
+// put inserts or overwrites key. The caller must hold the write lock.
+func (c *LRUCache[K, V]) put(key K, value V, expiry time.Duration) {
+	c.invalidateFill(key)
+	delete(c.holds, key)
+	c.dropSpilled(key)
+	if c.negative != nil {
+		c.negative.Remove(key)
+		c.callNegative.Remove(key)
 	}
 
-	if elem, found := c.cache[key]; found {
-		c.order.MoveToFront(elem)
-		item := elem.Value.(*CacheItem[K, V])
-		item.value = value
-		item.timestamp = time.Now()
-		item.expiry = expiry
+	if c.admitter != nil {
+		c.admitter.record(key)
+	}
+
+	if item, found := c.cache[key]; found {
+		c.policy.touch(item)
+		if c.setValue(item, value) {
+			item.touch(c.now())
+			item.expiry = expiry
+		}
 		return
 	}
 
-	if len(c.cache) >= c.capacity {
-		c.evict()
+	value, compressed := c.encode(value)
+	weight := c.weigh(key, value)
+	if c.overweight(weight) {
+		c.onReject(key)
+		return
+	}
+	if c.capacity > 0 && len(c.cache) >= c.capacity && c.options.lowWatermark > 0 {
+		if c.evictNow != nil && len(c.cache) < c.entryLimit() {
+			select {
+			case c.evictNow <- struct{}{}:
+			default:
+			}
+		} else {
+			c.evictDownTo(c.lowWatermark(), nil)
+		}
 	}
+	if c.capacity > 0 && len(c.cache) >= c.entryLimit() {
+		if ghosts, ok := c.policy.(ghostPolicy[K, V]); ok {
+			ghosts.admitting(key)
+		}
+		victim := c.victim(nil)
+		if victim == nil && c.pinned == len(c.cache) {
+			// Every entry is pinned, so nothing can make room.
+			c.onReject(key)
+			return
+		}
+		if victim != nil {
+			if c.admitter != nil && !c.admitter.admit(key, victim.key) {
+				c.onReject(key)
+				return
+			}
+			c.evictItem(victim)
+		}
+	}
+
+	item := c.newItem(key, value, expiry)
+	item.compressed = compressed
+	item.weight = weight
+	c.weight += weight
+	c.policy.add(item)
+	c.cache[key] = item
+	if c.idle {
+		c.idle = false
+		select {
+		case c.wake <- struct{}{}:
+		default:
+		}
+	}
+	c.evictToWeight(item)
+	c.evictDownTo(c.entryLimit(), item)
+}
+
+// weigh returns the weight of an entry, or zero when no weigher is set.
+func (c *LRUCache[K, V]) weigh(key K, value V) int64 {
+	if c.options.weigher == nil {
+		return 0
+	}
+	return c.options.weigher(key, value)
+}
 
-	item := &CacheItem[K, V]{key, value, time.Now(), expiry}
-	elem := c.order.PushFront(item)
-	c.cache[key] = elem
+// tooHeavy reports whether value, in the form it would be stored in, could
+// never fit under the maximum weight.
+func (c *LRUCache[K, V]) tooHeavy(key K, value V) bool {
+	if c.options.weigher == nil {
+		return false
+	}
+	stored, _ := c.compress(value)
+	return c.overweight(c.weigh(key, stored))
 }
 
+// overweight reports whether an entry of the given weight could never fit.
+func (c *LRUCache[K, V]) overweight(weight int64) bool {
+	return c.options.weigher != nil && weight > c.options.maxWeight
+}
+
+// setValue replaces the value of item and re-weighs it, evicting other entries
+// if the total weight no longer fits. A value that alone exceeds the maximum
+// weight is rejected and item is removed; setValue reports whether the value
+// was stored.
+func (c *LRUCache[K, V]) setValue(item *CacheItem[K, V], value V) bool {
+	value, compressed := c.encode(value)
+	weight := c.weigh(item.key, value)
+	if c.overweight(weight) {
+		key := item.key
+		c.removeItem(item)
+		c.releaseItem(item)
+		c.onReject(key)
+		return false
+	}
+	c.weight += weight - item.weight
+	item.weight = weight
+	item.value = value
+	item.compressed = compressed
+	c.evictToWeight(item)
+	return true
+}
+
+// evictToWeight evicts entries other than keep until the total weight is
+// within the maximum weight, or nothing else can be evicted.
+func (c *LRUCache[K, V]) evictToWeight(keep *CacheItem[K, V]) {
+	for c.options.weigher != nil && c.weight > c.options.maxWeight {
+		victim := c.victim(keep)
+		if victim == nil {
+			return
+		}
+		c.evictItem(victim)
+	}
+}
+
+// Get returns the value stored under key, loading it from the backing store
+// when the cache has no live entry. OnHit fires only when a live value is
+// served from memory; an entry found expired fires OnExpire and then counts
+// as a miss.
 func (c *LRUCache[K, V]) Get(key K) V {
-	c.mutex.RLock()
+	value, _ := c.GetOk(key)
+	return value
+}
 
-	if elem, found := c.cache[key]; found {
-		c.cacheListener.OnHit(key)
-		item := elem.Value.(*CacheItem[K, V])
-		if time.Since(item.timestamp) > item.expiry {
-			c.cacheListener.OnExpire(item.key)
-			c.order.Remove(elem)
-			delete(c.cache, key)
-			c.mutex.RUnlock()
-			return c.fetchFromBackingStore(key)
+func (c *LRUCache[K, V]) GetOk(key K) (V, bool) {
+	value, err := c.get(context.Background(), key)
+	return value, err == nil
+}
+
+// GetE is like Get but says why no value was returned: ErrClosed after Close,
+// ErrNotFound when neither the cache nor the backing store has the key, and a
+// *LoadError when the load failed or timed out.
+func (c *LRUCache[K, V]) GetE(key K) (V, error) {
+	if c.closed.Load() {
+		var zeroValue V
+		return zeroValue, ErrClosed
+	}
+	return c.get(context.Background(), key)
+}
+
+// GetMulti looks up every key in keys as Get would, loading misses from the
+// backing store, and returns the values found. Keys found nowhere are left out.
+func (c *LRUCache[K, V]) GetMulti(keys []K) map[K]V {
+	values, _ := c.GetMultiOk(keys)
+	return values
+}
+
+// GetMultiOk is GetMulti that also returns the keys that neither the cache nor
+// the backing store had, in the order they first appear in keys. Each key is
+// looked up once, however often it is repeated, and fires its own listener
+// callbacks.
+func (c *LRUCache[K, V]) GetMultiOk(keys []K) (map[K]V, []K) {
+	values := make(map[K]V, len(keys))
+	var missing []K
+	seen := make(map[K]struct{}, len(keys))
+	for _, key := range keys {
+		if _, done := seen[key]; done {
+			continue
+		}
+		seen[key] = struct{}{}
+		if value, found := c.GetOk(key); found {
+			values[key] = value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return values, missing
+}
+
+// get reads key for Get and its variants.
+func (c *LRUCache[K, V]) get(ctx context.Context, key K) (V, error) {
+	return c.lookup(ctx, c.normalize(key))
+}
+
+// lookup is get for a normalized key. It takes the write lock rather than the
+// read lock: a hit moves the entry to the front of the order list and
+// refreshes its timestamp, and an expired entry is removed, all of which
+// mutate shared state. Policies that do not reorder entries on reads, and
+// approximate LRU for hits that skip promotion, serve live hits under the
+// read lock instead.
+func (c *LRUCache[K, V]) lookup(ctx context.Context, key K) (V, error) {
+	if c.sharedHits {
+		if value, found := c.getShared(ctx, key); found {
+			return value, nil
+		}
+	}
+
+	c.mutex.Lock()
+
+	if item, found := c.cache[key]; found {
+		if c.admitter != nil {
+			c.admitter.record(key)
+		}
+		value, ok := c.read(item)
+		prior := item.expiry
+		if !ok {
+			c.removeItem(item)
+			c.releaseItem(item)
+			c.onMiss(ctx, key)
+			c.mutex.Unlock()
+			value, _, err := c.fetchFromBackingStore(ctx, key, prior)
+			return value, err
+		}
+		if item.expiredAt(c.now()) {
+			if c.options.allowStaleRead {
+				// Leave the entry for the janitor to expire.
+				c.onHit(ctx, key)
+				c.mutex.Unlock()
+				return value, nil
+			}
+			// An expired entry is a miss: it fires OnExpire, then OnMiss.
+			c.removeItem(item)
+			c.onExpire(item)
+			c.releaseItem(item)
+			c.onMiss(ctx, key)
+			c.mutex.Unlock()
+			value, _, err := c.fetchFromBackingStore(ctx, key, prior)
+			return value, err
+		}
+		c.onHit(ctx, key)
+		c.policy.touch(item)
+		now := c.now()
+		c.refreshAhead(item, now)
+		item.touch(now)
+		c.mutex.Unlock()
+		return value, nil
+	}
+
+	c.onMiss(ctx, key)
+	c.mutex.Unlock()
+	value, _, err := c.fetchFromBackingStore(ctx, key, 0)
+	return value, err
+}
+
+// GetWithTTL returns the value stored under key together with how long the
+// entry had left to live when it was read, under a single lock acquisition.
+// Like Get it counts as a use, which promotes the entry and restarts its TTL.
+// A value loaded from the backing store reports the TTL it was stored with,
+// or zero when WithCacheBackingResults(false) keeps it out of the cache.
+func (c *LRUCache[K, V]) GetWithTTL(key K) (V, time.Duration, bool) {
+	key = c.normalize(key)
+	c.mutex.Lock()
+
+	var prior time.Duration
+	if item, found := c.cache[key]; found {
+		prior = item.expiry
+		now := c.now()
+		value, ok := c.read(item)
+		if !ok {
+			c.removeItem(item)
+			c.releaseItem(item)
+			c.onMiss(context.Background(), key)
+		} else if !item.expiredAt(now) {
+			c.onHit(context.Background(), key)
+			if c.admitter != nil {
+				c.admitter.record(key)
+			}
+			remaining := item.remaining(now)
+			c.policy.touch(item)
+			item.touch(now)
+			c.mutex.Unlock()
+			return value, remaining, true
+		} else {
+			c.removeItem(item)
+			c.onExpire(item)
+			c.releaseItem(item)
+			c.onMiss(context.Background(), key)
 		}
-		c.order.MoveToFront(elem)
-		item.timestamp = time.Now()
-		value := item.value
-		c.mutex.RUnlock()
-		return value
+	} else {
+		c.onMiss(context.Background(), key)
 	}
+	c.mutex.Unlock()
 
-	c.cacheListener.OnMiss(key)
-	c.mutex.RUnlock()
-	return c.fetchFromBackingStore(key)
+	value, ttl, err := c.fetchFromBackingStore(context.Background(), key, prior)
+	if err != nil || !c.options.cacheBackingResults {
+		return value, 0, err == nil
+	}
+	return value, ttl, true
+}
+
+// getShared serves a live hit under the read lock when the policy can record
+// it there, or under approximate LRU when the entry is not picked for
+// promotion. Anything else, including an expired entry, reports false and is
+// left to the write-locked path in GetOk.
+func (c *LRUCache[K, V]) getShared(ctx context.Context, key K) (V, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var zeroValue V
+	item, found := c.cache[key]
+	if !found {
+		return zeroValue, false
+	}
+	now := c.now()
+	if item.expiredAt(now) {
+		return zeroValue, false
+	}
+	value, ok := c.read(item)
+	if !ok {
+		return zeroValue, false
+	}
+	if c.policy.sharedHits() {
+		c.policy.touchShared(item)
+	} else if rand.IntN(c.options.promoteOneIn) == 0 {
+		return zeroValue, false
+	}
+	c.onHit(ctx, key)
+	c.refreshAhead(item, now)
+	item.touch(now)
+	return value, true
 }
 
 func (c *LRUCache[K, V]) Remove(key K) {
+	key = c.normalize(key)
+	defer c.publishInvalidation(false, key)
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	if elem, found := c.cache[key]; found {
-		c.order.Remove(elem)
-		delete(c.cache, key)
+	c.removeKey(key)
+}
+
+// removeKey removes key, already normalized, on behalf of Remove and the
+// other removal methods: loads of key in flight are discarded, WithRemoveHold
+// holds it, and its entry, if any, in memory or spilled, is reported to the
+// listener. It returns whether there was an entry. Publishing the removal is
+// left to the caller, which must hold the write lock and publish once it has
+// released it.
+func (c *LRUCache[K, V]) removeKey(key K) bool {
+	c.invalidateFill(key)
+	c.hold(key)
+	found := c.dropSpilled(key)
+	if item, inMemory := c.cache[key]; inMemory {
+		c.removeItem(item)
+		c.releaseItem(item)
+		found = true
 	}
+	if found {
+		c.onRemove(key)
+	}
+	return found
 }
 
-func (c *LRUCache[K, V]) evict() {
-	if elem := c.order.Back(); elem != nil {
-		item := elem.Value.(*CacheItem[K, V])
-		delete(c.cache, item.key)
-		c.cacheListener.OnEvict(item.key)
-		c.order.Remove(elem)
+// RemoveAll removes every key in keys under a single lock acquisition and
+// returns how many of them were present. Keys that are absent, or repeated in
+// keys, are ignored.
+func (c *LRUCache[K, V]) RemoveAll(keys []K) int {
+	normalized := make([]K, 0, len(keys))
+	defer func() { c.publishInvalidation(false, normalized...) }()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	removed := 0
+	for _, key := range keys {
+		key = c.normalize(key)
+		normalized = append(normalized, key)
+		if c.removeKey(key) {
+			removed++
+		}
 	}
+	return removed
 }
 
-func (c *LRUCache[K, V]) fetchFromBackingStore(key K) V {
+// ReplaceAll replaces the whole contents of the cache with items under a
+// single write lock, so readers see either the old dataset or the new one and
+// never a mix or an empty cache in between. Keys missing from items are
+// removed as Remove removes them; every key in items is stored with ttl, or
+// the default TTL, as Put would store it. Both are published on the
+// WithInvalidationBus bus like the Remove and Put they stand for.
+func (c *LRUCache[K, V]) ReplaceAll(items map[K]V, ttl ...time.Duration) {
+	if c.options.normalize != nil {
+		normalized := make(map[K]V, len(items))
+		for key, value := range items {
+			normalized[c.normalize(key)] = value
+		}
+		items = normalized
+	}
+
+	var removed, stored []K
+	defer func() {
+		c.publishInvalidation(false, removed...)
+		c.publishInvalidation(true, stored...)
+	}()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.invalidateFills()
+	spilled := c.spilledKeys(func(key K, _ spilledEntry) bool {
+		_, keep := items[key]
+		return !keep
+	})
+	for key := range c.cache {
+		if _, keep := items[key]; !keep {
+			c.removeKey(key)
+			removed = append(removed, key)
+		}
+	}
+	for _, key := range spilled {
+		c.removeKey(key)
+		removed = append(removed, key)
+	}
+	expiry := c.expiryFor(ttl)
+	for key, value := range items {
+		c.put(key, value, expiry)
+		stored = append(stored, key)
+	}
+}
+
+// GetAndRemove atomically returns the value stored for key and removes it as
+// Remove does, so that no other caller can observe the value afterwards. A
+// WithSpill entry is read back from the store first. An expired entry is
+// dropped and reported as not found. The backing store is not consulted.
+func (c *LRUCache[K, V]) GetAndRemove(key K) (V, bool) {
+	key = c.normalize(key)
+	defer c.publishInvalidation(false, key)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	var zeroValue V
-	if value, found := c.backingStore(key); found {
-		c.Put(key, value)
-		return value
+	if item, found := c.cache[key]; found && item.expiredAt(c.now()) {
+		c.removeItem(item)
+		c.onExpire(item)
+		c.releaseItem(item)
+	}
+	var value V
+	var found bool
+	if item, inMemory := c.cache[key]; inMemory {
+		value, found = c.valueOf(item)
+	} else {
+		value, found = c.spilledValue(key)
+	}
+	if !c.removeKey(key) || !found {
+		return zeroValue, false
+	}
+	return value, true
+}
+
+// CompareAndSwapFunc replaces the value stored for key with newValue, but only
+// if the key is present, unexpired, and equal reports that its current value
+// matches oldValue. It reports whether the swap took place.
+func (c *LRUCache[K, V]) CompareAndSwapFunc(key K, oldValue, newValue V, equal func(a, b V) bool) bool {
+	key = c.normalize(key)
+	var stored []K
+	defer func() { c.publishInvalidation(true, stored...) }()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, found := c.cache[key]
+	if !found {
+		return false
+	}
+	if item.expiredAt(c.now()) {
+		return false
+	}
+	if current, ok := c.valueOf(item); !ok || !equal(current, oldValue) {
+		return false
+	}
+	c.policy.touch(item)
+	if !c.setValue(item, newValue) {
+		return false
+	}
+	item.touch(c.now())
+	stored = append(stored, key)
+	return true
+}
+
+// GetOrSet returns the value stored for key if it is present and unexpired,
+// counting the read as a use. Otherwise it stores value with ttl, or the
+// default TTL, and returns it. The check and the store happen under one lock,
+// so concurrent callers all get the same value, whichever stored it first. The
+// backing store is not consulted. A value turned away by admission or for
+// being overweight is still returned.
+func (c *LRUCache[K, V]) GetOrSet(key K, value V, ttl ...time.Duration) V {
+	key = c.normalize(key)
+	var stored []K
+	defer func() { c.publishInvalidation(true, stored...) }()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if item, found := c.cache[key]; found {
+		current, ok := c.read(item)
+		if ok && !item.expiredAt(c.now()) {
+			c.onHit(context.Background(), key)
+			c.policy.touch(item)
+			item.touch(c.now())
+			return current
+		}
+		c.removeItem(item)
+		if ok {
+			c.onExpire(item)
+		}
+		c.releaseItem(item)
+	}
+	c.put(key, value, c.expiryFor(ttl))
+	stored = append(stored, key)
+	return value
+}
+
+// PutAndReturnOld stores value under key like Put and returns the value it
+// replaced, with true, if the key held a live entry. Reading the old value
+// and storing the new one happen under one lock acquisition, so no other
+// write can slip in between.
+func (c *LRUCache[K, V]) PutAndReturnOld(key K, value V, ttl ...time.Duration) (old V, replaced bool) {
+	key = c.normalize(key)
+	defer c.publishInvalidation(true, key)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if item, found := c.cache[key]; found && !item.expiredAt(c.now()) {
+		old, replaced = c.read(item)
+	}
+	c.put(key, value, c.expiryFor(ttl))
+	return old, replaced
+}
+
+// CompareAndSwap is CompareAndSwapFunc for caches whose values are comparable
+// with ==.
+func CompareAndSwap[K comparable, V comparable](c *LRUCache[K, V], key K, oldValue, newValue V) bool {
+	return c.CompareAndSwapFunc(key, oldValue, newValue, func(a, b V) bool { return a == b })
+}
+
+// RemovePrefix removes every entry of a string-keyed cache whose key starts
+// with prefix, under a single lock acquisition, and returns how many were
+// removed. Each key is removed as Remove removes it. It scans the whole cache.
+// Keys are matched after WithKeyNormalizer has normalized them, while prefix
+// is used as given.
+func RemovePrefix[V any](c *LRUCache[string, V], prefix string) int {
+	var keys []string
+	defer func() { c.publishInvalidation(false, keys...) }()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key := range c.cache {
+		if strings.HasPrefix(key, prefix) {
+			c.removeKey(key)
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range c.spilledKeys(func(key string, _ spilledEntry) bool { return strings.HasPrefix(key, prefix) }) {
+		c.removeKey(key)
+		keys = append(keys, key)
+	}
+	return len(keys)
+}
+
+// Len returns the number of entries currently held, including expired entries
+// that have not been cleaned up yet.
+func (c *LRUCache[K, V]) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return len(c.cache)
+}
+
+// Resize changes the capacity without losing the contents. Growing only
+// raises the limit. Shrinking immediately evicts entries in eviction order,
+// with the usual listener notifications, until the cache fits. Pinned entries
+// and entries vetoed by WithCanEvict are not evicted; if they alone exceed the
+// new capacity the cache stays over it, rejecting new keys while every entry
+// is pinned, and shrinks as they become evictable. The weight bound of
+// WithMaximumWeight is independent of the capacity and is left unchanged. A
+// capacity of zero or less makes the cache unbounded.
+func (c *LRUCache[K, V]) Resize(capacity int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.resize(capacity)
+}
+
+func (c *LRUCache[K, V]) resize(capacity int) {
+	c.capacity = max(capacity, 0)
+	if policy, ok := c.policy.(resizablePolicy); ok {
+		policy.resize(c.capacity)
+	}
+	c.evictDownTo(c.capacity, nil)
+}
+
+// evictDownTo evicts entries other than keep while the cache holds more than
+// limit entries and something can be evicted.
+func (c *LRUCache[K, V]) evictDownTo(limit int, keep *CacheItem[K, V]) {
+	for c.capacity > 0 && len(c.cache) > limit {
+		victim := c.victim(keep)
+		if victim == nil {
+			return
+		}
+		c.evictItem(victim)
+	}
+}
+
+// Capacity returns the current capacity, zero meaning unbounded.
+func (c *LRUCache[K, V]) Capacity() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.capacity
+}
+
+// Weight returns the total weight of the entries, which is zero unless
+// WithMaximumWeight is set.
+func (c *LRUCache[K, V]) Weight() int64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.weight
+}
+
+// Keys returns the resident keys, most recently used first.
+func (c *LRUCache[K, V]) Keys() []K {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	keys := make([]K, 0, len(c.cache))
+	c.policy.entries(func(item *CacheItem[K, V]) bool {
+		keys = append(keys, item.key)
+		return true
+	})
+	return keys
+}
+
+// EvictionCandidates returns up to n keys in the order they are due to be
+// evicted, next victim first, without removing them or changing their order.
+// Pinned entries and entries vetoed by WithCanEvict are left out. The order is
+// exact for the LRU, FIFO, LFU and SLRU policies; under the others it is the
+// order the policy keeps entries in, which the victim choice only
+// approximates.
+func (c *LRUCache[K, V]) EvictionCandidates(n int) []K {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var items []*CacheItem[K, V]
+	c.policy.entries(func(item *CacheItem[K, V]) bool {
+		items = append(items, item)
+		return true
+	})
+	var keys []K
+	for i := len(items) - 1; i >= 0 && len(keys) < n; i-- {
+		item := items[i]
+		if item.pinned {
+			continue
+		}
+		if c.options.canEvict != nil {
+			if value, ok := c.valueOf(item); ok && !c.options.canEvict(item.key, value) {
+				continue
+			}
+		}
+		keys = append(keys, item.key)
+	}
+	return keys
+}
+
+// Clear drops every entry without notifying the listener.
+func (c *LRUCache[K, V]) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.policy.clear()
+	c.invalidateFills()
+	c.cache = make(map[K]*CacheItem[K, V])
+	c.weight = 0
+	c.tags = nil
+	c.pinned = 0
+	c.priorities = nil
+	c.holds = nil
+	c.dropAllSpilled(false)
+}
+
+// victim returns the entry the policy would evict next, skipping skip and
+// entries that WithCanEvict vetoes, or nil when no entry can be evicted. When
+// PutWithPriority has given entries different priorities it returns the
+// policy's first candidate of the lowest priority instead, which may take a
+// longer walk through the candidates.
+func (c *LRUCache[K, V]) victim(skip *CacheItem[K, V]) *CacheItem[K, V] {
+	lowest, prioritized := c.lowestPriority()
+	var victim *CacheItem[K, V]
+	c.policy.victims(func(item *CacheItem[K, V]) bool {
+		if item == skip || item.pinned {
+			return true
+		}
+		if victim != nil && item.priority >= victim.priority {
+			return true
+		}
+		if c.options.canEvict != nil {
+			if value, ok := c.valueOf(item); ok && !c.options.canEvict(item.key, value) {
+				return true
+			}
+		}
+		victim = item
+		return prioritized && item.priority > lowest
+	})
+	return victim
+}
+
+func (c *LRUCache[K, V]) evictItem(victim *CacheItem[K, V]) {
+	tags := victim.tags
+	c.removeItem(victim)
+	if ghosts, ok := c.policy.(ghostPolicy[K, V]); ok {
+		ghosts.evicted(victim)
+	}
+	c.evictions.record(c.now())
+	c.spill(victim, tags)
+	c.onEvict(victim.key)
+	c.releaseItem(victim)
+}
+
+func (c *LRUCache[K, V]) newItem(key K, value V, expiry time.Duration) *CacheItem[K, V] {
+	item := c.itemPool.Get().(*CacheItem[K, V])
+	item.key = key
+	item.value = value
+	item.touch(c.now())
+	item.expiry = expiry
+	return item
+}
+
+// removeItem unlinks item from the order list and the map. The caller must
+// release the item once it no longer needs it.
+func (c *LRUCache[K, V]) removeItem(item *CacheItem[K, V]) {
+	if item.pinned {
+		c.pinned--
+	}
+	c.untag(item)
+	c.unprioritize(item)
+	c.weight -= item.weight
+	c.policy.remove(item)
+	delete(c.cache, item.key)
+	c.invalidateFill(item.key)
+}
+
+// releaseItem returns item to the pool, clearing it first so that pooled items
+// do not keep user keys and values reachable.
+func (c *LRUCache[K, V]) releaseItem(item *CacheItem[K, V]) {
+	*item = CacheItem[K, V]{}
+	c.itemPool.Put(item)
+}
+
+// fetchFromBackingStore loads key after a miss and reports the outcome to a
+// LoadListener.
+func (c *LRUCache[K, V]) fetchFromBackingStore(ctx context.Context, key K, prior time.Duration) (V, time.Duration, error) {
+	var (
+		value V
+		ttl   time.Duration
+		route int
+		err   error
+	)
+	start := c.now()
+	if c.held(key) {
+		err = ErrNotFound
+	} else {
+		value, ttl, route, err = c.fetchSerialized(ctx, key, prior)
+		if err == ErrDoNotCache {
+			err = nil
+		}
+	}
+	if err == nil {
+		c.publishLoad(key, route, c.now().Sub(start))
+	}
+	if _, ok := c.cacheListener.(LoadListener[K]); ok {
+		if err == nil {
+			c.notifyContext(ctx, EventLoad, key)
+		} else {
+			c.notifyContext(ctx, EventMiss, key)
+		}
+	}
+	return value, ttl, err
+}
+
+// keepNoExpiry is the prior of fetch for a never-expiring entry whose TTL a
+// refresh keeps, zero standing for no prior TTL.
+const keepNoExpiry time.Duration = -1
+
+// fetch loads key and caches the value found with the TTL chosen by fillTTL,
+// which it returns along with the WithLoaderFor route that loaded it, as
+// load does. prior is the TTL of the entry being refreshed, or zero, or
+// keepNoExpiry. A value the loader returned with ErrDoNotCache is returned
+// uncached, with a TTL of zero and ErrDoNotCache. The misses of a
+// GetWithLoader loader are remembered in the negative cache of its override,
+// if any, rather than the cache's own, which they neither consult nor fill.
+func (c *LRUCache[K, V]) fetch(ctx context.Context, key K, prior time.Duration) (V, time.Duration, int, error) {
+	var zeroValue V
+	negative := c.negative
+	if override, overridden := overriddenLoader(ctx, c, key); overridden {
+		negative = override.negative
+	}
+	if negative != nil && negative.containsLive(key) {
+		return zeroValue, 0, 0, ErrNotFound
+	}
+	f := c.beginFill(key)
+	if ctx.Value(reloadKey{}) == nil {
+		if value, entry, found := c.unspill(key); found {
+			c.endUnspill(key, f, value, entry)
+			return value, entry.ttl, 0, nil
+		}
+	}
+	value, related, loaded, route, err := c.loadFromBackingStore(ctx, key)
+	if len(related) > 0 && c.options.cacheBackingResults {
+		c.prefetch(key, related)
+	}
+	var ttl time.Duration
+	if err == nil {
+		ttl = c.fillTTL(loaded, prior)
+	}
+	c.endFill(key, f, value, ttl, err, negative)
+	if err == ErrDoNotCache {
+		return value, 0, route, err
+	}
+	if err != nil {
+		return zeroValue, 0, 0, err
+	}
+	if c.options.cacheBackingResults && c.options.cloneValue != nil && !c.options.cloneOnPut {
+		value = c.options.cloneValue(value)
+	}
+	return value, ttl, route, nil
+}
+
+// fillTTL picks the TTL of a value loaded from the backing store, in order of
+// precedence: the TTL the WithLoaderTTL loader returned with it, the TTL of
+// the expired entry it replaces, the WithFillTTL TTL and the default TTL.
+// Zero stands for no preference at each step; a prior of keepNoExpiry keeps
+// the value from expiring.
+func (c *LRUCache[K, V]) fillTTL(loaded, prior time.Duration) time.Duration {
+	switch {
+	case loaded > 0:
+		return loaded
+	case prior == keepNoExpiry:
+		return 0
+	case prior > 0:
+		return prior
+	case c.options.fillTTL > 0:
+		return c.options.fillTTL
+	default:
+		return c.defaultTTL
+	}
+}
+
+// loadFromBackingStore calls the backing store, or the WithPrefetchLoader
+// loader, which also returns related entries, giving up after the configured
+// load timeout if there is one. A load that times out fails with a *LoadError
+// wrapping context.DeadlineExceeded; it keeps running in its own goroutine and
+// its result is discarded rather than cached, since by then the caller has
+// already moved on.
+func (c *LRUCache[K, V]) loadFromBackingStore(ctx context.Context, key K) (value V, related map[K]V, ttl time.Duration, route int, err error) {
+	if c.options.loadTimeout <= 0 {
+		return c.load(ctx, key)
+	}
+
+	type result struct {
+		value   V
+		related map[K]V
+		ttl     time.Duration
+		route   int
+		err     error
+	}
+	// The first tick of a ticker on the cache's clock is the deadline, so
+	// that a fake clock can time loads out.
+	deadline, stop := c.options.clock.NewTicker(c.options.loadTimeout)
+	defer stop()
+	results := make(chan result, 1)
+	go func() {
+		value, related, ttl, route, err := c.load(ctx, key)
+		results <- result{value, related, ttl, route, err}
+	}()
+
+	select {
+	case r := <-results:
+		return r.value, r.related, r.ttl, r.route, r.err
+	case <-deadline:
+		c.stats.loadTimeouts.Add(1)
+		return value, nil, 0, 0, &LoadError{Key: key, Err: context.DeadlineExceeded}
+	}
+}
+
+// load calls the GetWithLoader loader of the read, or else the WithLoaderFor
+// loader key routes to, or else whichever loader is configured, and returns
+// the TTL the loader chose, if any, along with the value, and the position,
+// from 1, of the route that loaded it, or zero if it was another loader. It
+// returns ErrNotFound when the key does not exist, ErrDoNotCache with a value
+// that must not be stored, and wraps any other failure in a *LoadError.
+func (c *LRUCache[K, V]) load(ctx context.Context, key K) (V, map[K]V, time.Duration, int, error) {
+	if c.loads != nil {
+		c.loads <- struct{}{}
+		defer func() { <-c.loads }()
+	}
+	var (
+		zeroValue, value V
+		ttl              time.Duration
+		err              error
+	)
+	override, overridden := overriddenLoader(ctx, c, key)
+	route := 0
+	if !overridden {
+		route = c.route(key)
+	}
+	switch {
+	case overridden:
+		found := false
+		if value, found = override.loader(key); !found {
+			err = ErrNotFound
+		}
+		ttl = override.ttl
+	case route > 0:
+		found := false
+		if value, found = c.options.loaderRoutes[route-1].loader(key); !found {
+			err = ErrNotFound
+		}
+	case c.options.prefetchLoader != nil:
+		value, related, found := c.options.prefetchLoader(key)
+		if !found {
+			return zeroValue, related, 0, 0, ErrNotFound
+		}
+		return value, related, 0, 0, nil
+	case c.options.loaderTTL != nil:
+		value, ttl, err = c.options.loaderTTL(key)
+	case c.options.loaderContext != nil:
+		value, err = c.options.loaderContext(withLoading(ctx, c, key), key)
+	case c.options.loaderE != nil:
+		value, err = c.options.loaderE(key)
+	default:
+		found := false
+		if value, found = c.backingStore(key); !found {
+			err = ErrNotFound
+		}
+	}
+	if errors.Is(err, ErrNotFound) {
+		return zeroValue, nil, 0, 0, ErrNotFound
+	}
+	if errors.Is(err, ErrDoNotCache) {
+		return value, nil, 0, route, ErrDoNotCache
+	}
+	if err != nil {
+		return zeroValue, nil, 0, 0, &LoadError{Key: key, Err: err}
+	}
+	return value, nil, ttl, route, nil
+}
+
+// prefetch stores the entries a loader returned alongside key, with the fill
+// TTL. Keys that already hold a live entry keep it.
+func (c *LRUCache[K, V]) prefetch(key K, related map[K]V) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	for relatedKey, value := range related {
+		relatedKey = c.normalize(relatedKey)
+		if relatedKey == key {
+			continue
+		}
+		if item, found := c.cache[relatedKey]; found && !item.expiredAt(now) {
+			continue
+		}
+		c.put(relatedKey, value, c.fillTTL(0, 0))
+	}
+}
+
+// TTL returns how long the entry for key has left to live, without counting
+// as a use, and false if key holds no live entry. An entry that never expires
+// reports zero.
+func (c *LRUCache[K, V]) TTL(key K) (time.Duration, bool) {
+	key = c.normalize(key)
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	item, found := c.cache[key]
+	now := c.now()
+	if !found || item.expiredAt(now) {
+		return 0, false
+	}
+	return item.remaining(now), true
+}
+
+// GetStale returns the value stored under key even if it has expired, for
+// callers that would rather show old data than none. found reports whether an
+// entry is resident at all and fresh whether it is still within its TTL. It
+// never consults the backing store, removes the entry or counts as a use, and
+// fires no events. Expired entries stay readable only until the janitor or
+// another lookup removes them.
+func (c *LRUCache[K, V]) GetStale(key K) (value V, fresh bool, found bool) {
+	key = c.normalize(key)
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	item, found := c.cache[key]
+	if !found {
+		return value, false, false
+	}
+	value, ok := c.read(item)
+	if !ok {
+		var zeroValue V
+		return zeroValue, false, false
+	}
+	return value, !item.expiredAt(c.now()), true
+}
+
+// Peek returns the value stored under key if its entry is live, without
+// counting as a use, firing events or consulting the backing store.
+func (c *LRUCache[K, V]) Peek(key K) (V, bool) {
+	value, _, found := c.liveValue(c.normalize(key))
+	return value, found
+}
+
+// Contains reports whether key holds an unexpired entry, without counting as
+// a use, firing events or consulting the backing store.
+func (c *LRUCache[K, V]) Contains(key K) bool {
+	key = c.normalize(key)
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	item, found := c.cache[key]
+	return found && !item.expiredAt(c.now())
+}
+
+// normalize applies the WithKeyNormalizer function, if any, to key.
+func (c *LRUCache[K, V]) normalize(key K) K {
+	if c.options.normalize == nil {
+		return key
+	}
+	return c.options.normalize(key)
+}
+
+// containsLive reports whether key holds an unexpired entry, dropping it if it
+// has expired. Unlike Get it neither refreshes the entry nor fires events.
+func (c *LRUCache[K, V]) containsLive(key K) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, found := c.cache[key]
+	if !found {
+		return false
+	}
+	if item.expiredAt(c.now()) {
+		c.removeItem(item)
+		c.releaseItem(item)
+		return false
 	}
-	return zeroValue
+	return true
 }
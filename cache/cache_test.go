@@ -1,8 +1,13 @@
 package cache
 
 import (
+	"fmt"
+	"slices"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/vivekkothari/in-memory-cache/cache/testutil/clocktest"
 )
 
 type CountingCacheListener[K comparable] struct {
@@ -10,6 +15,7 @@ type CountingCacheListener[K comparable] struct {
 	missMap   map[K]int
 	evictMap  map[K]int
 	expireMap map[K]int
+	removeMap map[K]int
 }
 
 func NewCountingCacheListener[K comparable]() *CountingCacheListener[K] {
@@ -18,6 +24,7 @@ func NewCountingCacheListener[K comparable]() *CountingCacheListener[K] {
 		missMap:   make(map[K]int),
 		evictMap:  make(map[K]int),
 		expireMap: make(map[K]int),
+		removeMap: make(map[K]int),
 	}
 }
 
@@ -37,15 +44,19 @@ func (l *CountingCacheListener[K]) OnExpire(key K) {
 	l.expireMap[key]++
 }
 
+func (l *CountingCacheListener[K]) OnRemove(key K) {
+	l.removeMap[key]++
+}
+
 // Helper function to create a new cache with a simple backing store
-func newTestCache(capacity int, defaultTTL time.Duration, listener CacheListener[string]) Cache[string, string] {
+func newTestCache(capacity int, defaultTTL time.Duration, listener CacheListener[string], opts ...Option[string, string]) Cache[string, string] {
 	backingStore := func(key string) (string, bool) {
 		if key == "keyX" {
 			return "valueX", true
 		}
 		return "", false
 	}
-	return NewLRUCache[string, string](capacity, defaultTTL, backingStore, listener, 5*time.Second)
+	return NewLRUCache[string, string](capacity, defaultTTL, backingStore, listener, 5*time.Second, opts...)
 }
 
 // Test Case 1: Add and Retrieve
@@ -105,6 +116,9 @@ func TestCacheRemove(t *testing.T) {
 	if value := cache.Get("key1"); value != "" {
 		t.Errorf("Expected '', got '%s'", value)
 	}
+	if value := listener.removeMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
 }
 
 // Test Case 5: Eviction Policy
@@ -127,10 +141,11 @@ func TestCacheEviction(t *testing.T) {
 // Test Case 6: Expiration of Cached Items
 func TestCacheExpiration(t *testing.T) {
 	listener := NewCountingCacheListener[string]()
-	cache := newTestCache(2, 2*time.Second, listener)
+	clock := clocktest.New(time.Now())
+	cache := newTestCache(2, 2*time.Second, listener, WithClock[string, string](clock))
 
 	cache.Put("key1", "value1")
-	time.Sleep(3 * time.Second) // Wait for expiration
+	clock.Advance(3 * time.Second) // Wait for expiration
 
 	if value := cache.Get("key1"); value != "" {
 		t.Errorf("Expected '', got '%s'", value)
@@ -138,15 +153,26 @@ func TestCacheExpiration(t *testing.T) {
 	if value := listener.expireMap["key1"]; value != 1 {
 		t.Errorf("Expected '1', got '%d'", value)
 	}
+	// An expired entry is a miss, not a hit.
+	if value := listener.hitMap["key1"]; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+	if value := listener.missMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if stats := cache.(*LRUCache[string, string]).Stats(); stats.Hits != 0 || stats.Misses != 1 {
+		t.Errorf("Expected 0 hits and 1 miss, got %d and %d", stats.Hits, stats.Misses)
+	}
 }
 
 // Test Case 7: Refresh from Backing Store
 func TestCacheRefreshFromBackingStore(t *testing.T) {
 	listener := NewCountingCacheListener[string]()
-	cache := newTestCache(2, 2*time.Second, listener)
+	clock := clocktest.New(time.Now())
+	cache := newTestCache(2, 2*time.Second, listener, WithClock[string, string](clock))
 
 	cache.Put("keyX", "staleValue")
-	time.Sleep(3 * time.Second) // Let it expire
+	clock.Advance(3 * time.Second) // Let it expire
 
 	if value := cache.Get("keyX"); value != "valueX" {
 		t.Errorf("Expected 'valueX' from backing store, got '%s'", value)
@@ -156,10 +182,11 @@ func TestCacheRefreshFromBackingStore(t *testing.T) {
 // Test Case 8: Key specific expiration of Cached Items
 func TestKeySpecificCacheExpiration(t *testing.T) {
 	listener := NewCountingCacheListener[string]()
-	cache := newTestCache(2, 5*time.Second, listener)
+	clock := clocktest.New(time.Now())
+	cache := newTestCache(2, 5*time.Second, listener, WithClock[string, string](clock))
 
 	cache.Put("key1", "value1", 1*time.Second)
-	time.Sleep(1 * time.Second) // Wait for expiration
+	clock.Advance(2 * time.Second) // Wait for expiration
 
 	if value := cache.Get("key1"); value != "" {
 		t.Errorf("Expected '', got '%s'", value)
@@ -172,11 +199,1013 @@ func TestKeySpecificCacheExpiration(t *testing.T) {
 // Test Case 8: Key specific expiration of Cached Items
 func TestAutoCleanupByBackgroundThread(t *testing.T) {
 	listener := NewCountingCacheListener[string]()
-	cache := NewLRUCache[string, string](2, 1*time.Second, nil, listener, 1*time.Second)
+	clock := clocktest.New(time.Now())
+	cache := NewLRUCache[string, string](2, 1*time.Second, nil, listener, 1*time.Second,
+		WithClock[string, string](clock))
+	cache.Put("key1", "value1")
+	clock.Advance(2 * time.Second) // Wait for expiration and a cleanup tick
+	cache.Close()                  // Waits for the cleanup goroutine to finish the tick
+	if value := listener.expireMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 9: Sampled cleanup expires stale entries in bounded rounds
+func TestSampledCleanup(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](100, 50*time.Millisecond, nil, listener, time.Hour,
+		WithSampledCleanup[string, string](10, 3))
+	defer cache.Close()
+
+	for i := 0; i < 100; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+	time.Sleep(100 * time.Millisecond) // Wait for expiration
+	cache.cleanupExpiredEntries()
+
+	stats := cache.LastCleanupStats()
+	if stats.Rounds != 3 {
+		t.Errorf("Expected '3' rounds, got '%d'", stats.Rounds)
+	}
+	if stats.Sampled != 30 || stats.Expired != 30 {
+		t.Errorf("Expected 30 sampled and expired, got %d sampled and %d expired", stats.Sampled, stats.Expired)
+	}
+	if value := len(listener.expireMap); value != 30 {
+		t.Errorf("Expected '30', got '%d'", value)
+	}
+}
+
+// Test Case 10: Vetoed entries are never evicted
+func TestCanEvictVeto(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second,
+		WithCanEvict(func(key string, value string) bool { return key != "pinned" }))
+
+	cache.Put("pinned", "value")
+	for i := 0; i < 5; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+
+	if value := cache.Get("pinned"); value != "value" {
+		t.Errorf("Expected 'value', got '%s'", value)
+	}
+	if value := listener.evictMap["pinned"]; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+	for i := 0; i < 4; i++ {
+		if value := listener.evictMap[fmt.Sprint("key", i)]; value != 1 {
+			t.Errorf("Expected '1', got '%d'", value)
+		}
+	}
+}
+
+// Test Case 11: Cache grows beyond capacity when every entry is vetoed
+func TestCanEvictVetoAll(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second,
+		WithCanEvict(func(string, string) bool { return false }))
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3")
+
+	for _, key := range []string{"key1", "key2", "key3"} {
+		if _, found := cache.GetOk(key); !found {
+			t.Errorf("Expected '%s' to be present", key)
+		}
+	}
+	if value := len(listener.evictMap); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 12: Concurrent readers and writers, meant to be run with -race
+func TestConcurrentAccess(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestCache(50, 5*time.Second, listener)
+	defer cache.Close()
+
+	for i := 0; i < 10; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := fmt.Sprint("key", (g+i)%10)
+				switch {
+				case g == 0 && i%10 == 0:
+					cache.Put(key, "value")
+				case g == 1 && i%10 == 0:
+					cache.Remove(key)
+				default:
+					cache.Get(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if value := cache.Get("keyX"); value != "valueX" {
+		t.Errorf("Expected 'valueX', got '%s'", value)
+	}
+}
+
+// Test Case 13: Compare-and-swap only replaces the expected value
+func TestCompareAndSwap(t *testing.T) {
+	cache := NewLRUCache[string, int](2, 5*time.Second, nil, NewCountingCacheListener[string](), 5*time.Second)
+
+	if CompareAndSwap(cache, "key1", 0, 1) {
+		t.Errorf("Expected swap of an absent key to fail")
+	}
+	cache.Put("key1", 1)
+	if CompareAndSwap(cache, "key1", 2, 3) {
+		t.Errorf("Expected swap with a stale old value to fail")
+	}
+	if !CompareAndSwap(cache, "key1", 1, 2) {
+		t.Errorf("Expected swap to succeed")
+	}
+	if value := cache.Get("key1"); value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+}
+
+// Test Case 14: Exactly one of two concurrent compare-and-swaps succeeds
+func TestCompareAndSwapConcurrent(t *testing.T) {
+	cache := NewLRUCache[string, int](2, 5*time.Second, nil, NewCountingCacheListener[string](), 5*time.Second)
+	cache.Put("key1", 0)
+
+	var wg sync.WaitGroup
+	results := make([]bool, 2)
+	for g := range results {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			results[g] = CompareAndSwap(cache, "key1", 0, g+1)
+		}(g)
+	}
+	wg.Wait()
+
+	if results[0] == results[1] {
+		t.Errorf("Expected exactly one swap to succeed, got %v", results)
+	}
+	winner := 1
+	if results[1] {
+		winner = 2
+	}
+	if value := cache.Get("key1"); value != winner {
+		t.Errorf("Expected '%d', got '%d'", winner, value)
+	}
+}
+
+// Test Case 15: GetAndRemove pops the value exactly once
+func TestGetAndRemove(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second)
+
 	cache.Put("key1", "value1")
-	time.Sleep(2 * time.Second) // Wait for expiration
+	if value, found := cache.GetAndRemove("key1"); !found || value != "value1" {
+		t.Errorf("Expected 'value1', got '%s' (found=%t)", value, found)
+	}
+	if _, found := cache.GetAndRemove("key1"); found {
+		t.Errorf("Expected 'key1' to be gone")
+	}
+	if value := listener.removeMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 16: GetAndRemove treats an expired entry as absent
+func TestGetAndRemoveExpired(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second)
+
+	cache.Put("key1", "value1", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond) // Wait for expiration
+	if _, found := cache.GetAndRemove("key1"); found {
+		t.Errorf("Expected expired 'key1' to be reported as absent")
+	}
 	if value := listener.expireMap["key1"]; value != 1 {
 		t.Errorf("Expected '1', got '%d'", value)
 	}
-	cache.Close()
+}
+
+// Test Case 17: Only one of many concurrent GetAndRemove calls gets the value
+func TestGetAndRemoveConcurrent(t *testing.T) {
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	cache.Put("key1", "value1")
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	winners := 0
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, found := cache.GetAndRemove("key1"); found {
+				mutex.Lock()
+				winners++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if winners != 1 {
+		t.Errorf("Expected '1', got '%d'", winners)
+	}
+}
+
+// Test Case 18: Absent keys are remembered in a separate negative LRU
+func TestNegativeCache(t *testing.T) {
+	lookups := map[string]int{}
+	backingStore := func(key string) (string, bool) {
+		lookups[key]++
+		return "", false
+	}
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, backingStore, listener, 5*time.Second,
+		WithNegativeCache[string, string](2, 5*time.Second))
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+
+	cache.Get("absent1")
+	cache.Get("absent1")
+	if value := lookups["absent1"]; value != 1 {
+		t.Errorf("Expected '1' lookup, got '%d'", value)
+	}
+
+	cache.Get("absent2")
+	cache.Get("absent3") // Evicts absent1 from the negative cache
+	cache.Get("absent1")
+	if value := lookups["absent1"]; value != 2 {
+		t.Errorf("Expected '2' lookups, got '%d'", value)
+	}
+
+	if value := len(listener.evictMap); value != 0 {
+		t.Errorf("Expected no evictions of positive entries, got '%d'", value)
+	}
+	if value := cache.Get("key1"); value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+	if value := cache.Get("key2"); value != "value2" {
+		t.Errorf("Expected 'value2', got '%s'", value)
+	}
+}
+
+// Test Case 19: Negative entries expire and are cleared by Put
+func TestNegativeCacheExpiryAndPut(t *testing.T) {
+	lookups := 0
+	backingStore := func(key string) (string, bool) {
+		lookups++
+		return "", false
+	}
+	cache := NewLRUCache[string, string](2, 5*time.Second, backingStore, quietListener[string]{}, 5*time.Second,
+		WithNegativeCache[string, string](2, 50*time.Millisecond))
+
+	cache.Get("key1")
+	cache.Get("key1")
+	time.Sleep(100 * time.Millisecond) // Wait for the negative entry to expire
+	cache.Get("key1")
+	if lookups != 2 {
+		t.Errorf("Expected '2' lookups, got '%d'", lookups)
+	}
+
+	cache.Put("key1", "value1")
+	cache.Remove("key1")
+	cache.Get("key1")
+	if lookups != 3 {
+		t.Errorf("Expected '3' lookups, got '%d'", lookups)
+	}
+}
+
+// Test Case 20: Approximate LRU still evicts mostly cold entries
+func TestApproximateLRUEvictsColdEntries(t *testing.T) {
+	listener := NewCountingCacheListener[int]()
+	cache := NewLRUCache[int, int](100, 5*time.Second, nil, listener, 5*time.Second,
+		WithApproximateLRU[int, int](4))
+
+	for i := 0; i < 100; i++ {
+		cache.Put(i, i)
+	}
+	for round := 0; round < 20; round++ {
+		for i := 50; i < 100; i++ {
+			cache.Get(i)
+		}
+	}
+	for i := 100; i < 150; i++ {
+		cache.Put(i, i)
+	}
+
+	coldVictims := 0
+	for i := 0; i < 50; i++ {
+		coldVictims += listener.evictMap[i]
+	}
+	if value := len(listener.evictMap); value != 50 {
+		t.Errorf("Expected '50' evictions, got '%d'", value)
+	}
+	if coldVictims < 45 {
+		t.Errorf("Expected at least '45' cold victims, got '%d'", coldVictims)
+	}
+	if value := listener.hitMap[75]; value != 20 {
+		t.Errorf("Expected '20', got '%d'", value)
+	}
+}
+
+// Test Case 21: A hung backing store does not block Get past the load timeout
+func TestLoadTimeout(t *testing.T) {
+	backingStore := func(key string) (string, bool) {
+		time.Sleep(300 * time.Millisecond)
+		return "slowValue", true
+	}
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, backingStore, listener, 5*time.Second,
+		WithLoadTimeout[string, string](50*time.Millisecond))
+
+	start := time.Now()
+	if value, found := cache.GetOk("key1"); found || value != "" {
+		t.Errorf("Expected '', got '%s' (found=%t)", value, found)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Expected Get to return promptly, took %s", elapsed)
+	}
+	if value := listener.missMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Stats().LoadTimeouts; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+
+	time.Sleep(400 * time.Millisecond) // Let the abandoned load finish
+	if value := cache.Len(); value != 0 {
+		t.Errorf("Expected the late result to be discarded, got '%d' entries", value)
+	}
+}
+
+// Test Case 22: RemoveAll counts only keys that were present
+func TestRemoveAll(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](5, 5*time.Second, nil, listener, 5*time.Second)
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3")
+
+	if removed := cache.RemoveAll([]string{"key1", "absent", "key2", "key1"}); removed != 2 {
+		t.Errorf("Expected '2', got '%d'", removed)
+	}
+	if keys := cache.Keys(); len(keys) != 1 || keys[0] != "key3" {
+		t.Errorf("Expected [key3], got %v", keys)
+	}
+	if value := listener.removeMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := listener.removeMap["absent"]; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 23: Stale reads return an expired value without loading
+func TestAllowStaleRead(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	loads := 0
+	backingStore := func(key string) (string, bool) {
+		loads++
+		return "fresh", true
+	}
+	cache := NewLRUCache[string, string](5, 5*time.Second, backingStore, listener, 50*time.Millisecond,
+		WithAllowStaleRead[string, string]())
+
+	cache.Put("key1", "stale", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond) // Wait for expiration
+	if value := cache.Get("key1"); value != "stale" {
+		t.Errorf("Expected 'stale', got '%s'", value)
+	}
+	if loads != 0 {
+		t.Errorf("Expected no backing store call, got '%d'", loads)
+	}
+
+	time.Sleep(100 * time.Millisecond) // Let the cleanup goroutine run
+	if value := cache.Len(); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+	if value := cache.Get("key1"); value != "fresh" {
+		t.Errorf("Expected 'fresh', got '%s'", value)
+	}
+}
+
+// Test Case 24: Set overwrites without changing the eviction order
+func TestSetKeepsOrder(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](3, 5*time.Second, nil, listener, 5*time.Second)
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3")
+
+	cache.Set("key1", "updated", time.Second) // key1 stays least recently used
+	if keys := cache.Keys(); len(keys) != 3 || keys[2] != "key1" {
+		t.Errorf("Expected 'key1' last, got %v", keys)
+	}
+
+	cache.Put("key4", "value4")
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+
+	cache.Set("key2", "updated")
+	if value := cache.Get("key2"); value != "updated" {
+		t.Errorf("Expected 'updated', got '%s'", value)
+	}
+	cache.Set("key5", "value5") // A new key is inserted like Put
+	if value := listener.evictMap["key3"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 25: Backing store results are returned but not stored
+func TestDoNotCacheBackingResults(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	loads := 0
+	backingStore := func(key string) (string, bool) {
+		loads++
+		return "loaded", true
+	}
+	cache := NewLRUCache[string, string](5, 5*time.Second, backingStore, listener, 5*time.Second,
+		WithCacheBackingResults[string, string](false))
+
+	for i := 0; i < 2; i++ {
+		if value := cache.Get("key1"); value != "loaded" {
+			t.Errorf("Expected 'loaded', got '%s'", value)
+		}
+	}
+	if loads != 2 {
+		t.Errorf("Expected '2', got '%d'", loads)
+	}
+	if value := cache.Len(); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+
+	cache.Put("key2", "value2")
+	if value := cache.Get("key2"); value != "value2" {
+		t.Errorf("Expected 'value2', got '%s'", value)
+	}
+}
+
+// Test Case 26: GetWithTTL reports the remaining lifetime
+func TestGetWithTTL(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	backingStore := func(key string) (string, bool) { return "loaded", key == "keyX" }
+	cache := NewLRUCache[string, string](5, 5*time.Second, backingStore, listener, 5*time.Second)
+
+	cache.Put("key1", "value1", time.Second)
+	time.Sleep(50 * time.Millisecond)
+	value, first, found := cache.GetWithTTL("key1")
+	if !found || value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+	if first <= 0 || first > 950*time.Millisecond {
+		t.Errorf("Expected a remaining TTL below 950ms, got '%v'", first)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, second, _ := cache.GetWithTTL("key1"); second >= first {
+		t.Errorf("Expected less than '%v', got '%v'", first, second)
+	}
+	if value := listener.hitMap["key1"]; value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+
+	if value, ttl, found := cache.GetWithTTL("keyX"); !found || value != "loaded" || ttl != 5*time.Second {
+		t.Errorf("Expected 'loaded' for 5s, got '%s' for '%v'", value, ttl)
+	}
+	if _, _, found := cache.GetWithTTL("absent"); found {
+		t.Errorf("Expected 'absent' to be missing")
+	}
+}
+
+type loadCountingListener struct {
+	*CountingCacheListener[string]
+	loadMap map[string]int
+}
+
+func (l *loadCountingListener) OnLoad(key string) {
+	l.loadMap[key]++
+}
+
+// Test Case 27: A LoadListener sees OnMiss only when the backing store fails too
+func TestOnLoad(t *testing.T) {
+	listener := &loadCountingListener{CountingCacheListener: NewCountingCacheListener[string](), loadMap: map[string]int{}}
+	backingStore := func(key string) (string, bool) { return "loaded", key == "keyX" }
+	cache := NewLRUCache[string, string](5, 5*time.Second, backingStore, listener, 5*time.Second)
+
+	if value := cache.Get("keyX"); value != "loaded" {
+		t.Errorf("Expected 'loaded', got '%s'", value)
+	}
+	if value := listener.missMap["keyX"]; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+	if value := listener.loadMap["keyX"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+
+	cache.Get("absent")
+	if value := listener.missMap["absent"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := listener.loadMap["absent"]; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+	if value := cache.Stats().Misses; value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+}
+
+// Test Case 28: ReplaceAll swaps datasets without an observable gap
+func TestReplaceAll(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, listener, 5*time.Second)
+	cache.ReplaceAll(map[string]string{"old": "v1", "both": "v1"})
+
+	stop := make(chan struct{})
+	var observed sync.WaitGroup
+	observed.Add(1)
+	var gaps int
+	go func() {
+		defer observed.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			keys := cache.Keys()
+			if len(keys) != 2 && len(keys) != 3 {
+				gaps++
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		cache.ReplaceAll(map[string]string{"both": "v2", "new1": "v2", "new2": "v2"})
+		cache.ReplaceAll(map[string]string{"old": "v1", "both": "v1"})
+	}
+	cache.ReplaceAll(map[string]string{"both": "v2", "new1": "v2", "new2": "v2"}, time.Minute)
+	close(stop)
+	observed.Wait()
+
+	if gaps != 0 {
+		t.Errorf("Expected no partial states, got '%d'", gaps)
+	}
+	if _, found := cache.GetOk("old"); found {
+		t.Errorf("Expected 'old' to be gone")
+	}
+	for _, key := range []string{"both", "new1", "new2"} {
+		if value := cache.Get(key); value != "v2" {
+			t.Errorf("Expected 'v2', got '%s'", value)
+		}
+	}
+	if value := listener.removeMap["old"]; value != 101 {
+		t.Errorf("Expected '101', got '%d'", value)
+	}
+	if value := listener.removeMap["both"]; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 29: Capacities of 0 and -1 are unbounded, 1 holds a single entry
+func TestCapacityBoundaries(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		listener := NewCountingCacheListener[string]()
+		cache := NewLRUCache[string, string](capacity, 5*time.Second, nil, listener, 5*time.Second)
+		for i := 0; i < 100; i++ {
+			cache.Put(fmt.Sprint("key", i), "value")
+		}
+		if value := cache.Len(); value != 100 {
+			t.Errorf("Expected '100', got '%d'", value)
+		}
+		if value := cache.Stats(); value.Evictions != 0 || value.Capacity != 0 {
+			t.Errorf("Expected no evictions and capacity 0, got %+v", value)
+		}
+		cache.Put("short", "value", 10*time.Millisecond)
+		time.Sleep(20 * time.Millisecond) // Expiry still applies
+		if _, found := cache.GetOk("short"); found {
+			t.Errorf("Expected 'short' to expire")
+		}
+	}
+
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](1, 5*time.Second, nil, listener, 5*time.Second)
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	if keys := cache.Keys(); len(keys) != 1 || keys[0] != "key2" {
+		t.Errorf("Expected [key2], got %v", keys)
+	}
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 30: Concurrent GetOrSet calls agree on a single winning value
+func TestGetOrSet(t *testing.T) {
+	cache := NewLRUCache[string, int](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+
+	results := make([]int, 50)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = cache.GetOrSet("key", i+1)
+		}()
+	}
+	wg.Wait()
+
+	winner := cache.Get("key")
+	for _, value := range results {
+		if value != winner {
+			t.Errorf("Expected '%d', got '%d'", winner, value)
+		}
+	}
+
+	cache.Put("short", 1, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if value := cache.GetOrSet("short", 2); value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+}
+
+// Test Case 31: A prefetch loader warms the neighbours of a missed key
+func TestPrefetchLoader(t *testing.T) {
+	calls := 0
+	loader := func(key string) (string, map[string]string, bool) {
+		calls++
+		var row int
+		fmt.Sscanf(key, "row:%d", &row)
+		related := map[string]string{}
+		for _, neighbour := range []int{row - 1, row + 1} {
+			related[fmt.Sprint("row:", neighbour)] = fmt.Sprint("value", neighbour)
+		}
+		return fmt.Sprint("value", row), related, true
+	}
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithPrefetchLoader(loader))
+	defer cache.Close()
+
+	if value := cache.Get("row:5"); value != "value5" {
+		t.Errorf("Expected 'value5', got '%s'", value)
+	}
+	for key, expected := range map[string]string{"row:4": "value4", "row:6": "value6"} {
+		if value := cache.Get(key); value != expected {
+			t.Errorf("Expected '%s', got '%s'", expected, value)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected '1' loader call, got '%d'", calls)
+	}
+}
+
+// sizeAtEvictListener records the number of resident entries each time an
+// eviction is reported, from inside the cache's lock.
+type sizeAtEvictListener struct {
+	quietListener[string]
+	cache   *LRUCache[string, string]
+	evicted []string
+	sizes   []int
+}
+
+func (l *sizeAtEvictListener) OnEvict(key string) {
+	l.evicted = append(l.evicted, key)
+	l.sizes = append(l.sizes, len(l.cache.cache))
+}
+
+// Test Case 32: With capacity 1 every distinct key evicts its predecessor once
+func TestCapacityOneReplacement(t *testing.T) {
+	for _, policy := range []Policy{LRU, LFU, FIFO, SLRU, ARC, SecondChance, Random, SampledLRU} {
+		listener := &sizeAtEvictListener{}
+		cache := NewLRUCache[string, string](1, 5*time.Second, nil, listener, 5*time.Second,
+			WithPolicy[string, string](policy))
+		listener.cache = cache
+
+		for _, key := range []string{"key1", "key2", "key3"} {
+			cache.Put(key, "value")
+			cache.Put(key, "updated") // Overwriting the sole entry evicts nothing
+		}
+		cache.Close()
+
+		if !slices.Equal(listener.evicted, []string{"key1", "key2"}) {
+			t.Errorf("Policy %d: expected evictions [key1 key2], got %v", policy, listener.evicted)
+		}
+		if !slices.Equal(listener.sizes, []int{0, 0}) {
+			t.Errorf("Policy %d: expected the old entry to leave before the new one arrives, got sizes %v", policy, listener.sizes)
+		}
+		if keys := cache.Keys(); !slices.Equal(keys, []string{"key3"}) {
+			t.Errorf("Policy %d: expected [key3], got %v", policy, keys)
+		}
+	}
+}
+
+// Test Case 33: Cleanup pauses while the cache is empty and resumes on Put
+func TestCleanupPausesWhenEmpty(t *testing.T) {
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 10*time.Millisecond)
+	defer cache.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	paused := cache.stats.cleanups.Load()
+	if paused > 1 {
+		t.Errorf("Expected at most one scan before pausing, got '%d'", paused)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if value := cache.stats.cleanups.Load(); value != paused {
+		t.Errorf("Expected no scans while empty, got '%d' more", value-paused)
+	}
+
+	cache.Put("key", "value")
+	time.Sleep(50 * time.Millisecond)
+	resumed := cache.stats.cleanups.Load()
+	if resumed < paused+2 {
+		t.Errorf("Expected scans to resume after a Put, got '%d' more", resumed-paused)
+	}
+
+	cache.Remove("key")
+	time.Sleep(30 * time.Millisecond)
+	paused = cache.stats.cleanups.Load()
+	time.Sleep(50 * time.Millisecond)
+	if value := cache.stats.cleanups.Load(); value != paused {
+		t.Errorf("Expected scans to pause again once empty, got '%d' more", value-paused)
+	}
+}
+
+// Test Case 34: GetStale keeps serving an expired entry, flagged as stale
+func TestGetStale(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	clock := clocktest.New(time.Now())
+	cache := NewLRUCache[string, string](2, time.Second, func(key string) (string, bool) {
+		return "loaded", true
+	}, listener, time.Minute, WithClock[string, string](clock))
+	defer cache.Close()
+
+	cache.Put("key1", "value1")
+	if value, fresh, found := cache.GetStale("key1"); value != "value1" || !fresh || !found {
+		t.Errorf("Expected 'value1' fresh and found, got '%s' fresh=%t found=%t", value, fresh, found)
+	}
+
+	clock.Advance(2 * time.Second)
+	if value, fresh, found := cache.GetStale("key1"); value != "value1" || fresh || !found {
+		t.Errorf("Expected 'value1' stale and found, got '%s' fresh=%t found=%t", value, fresh, found)
+	}
+	if value := cache.Len(); value != 1 {
+		t.Errorf("Expected the expired entry to stay resident, got '%d' entries", value)
+	}
+	if value := listener.expireMap["key1"] + listener.missMap["key1"]; value != 0 {
+		t.Errorf("Expected no events, got '%d'", value)
+	}
+
+	if value, fresh, found := cache.GetStale("absent"); value != "" || fresh || found {
+		t.Errorf("Expected nothing for an absent key, got '%s' fresh=%t found=%t", value, fresh, found)
+	}
+}
+
+// Test Case 35: A read-through refresh keeps the expired entry's TTL
+func TestRefreshKeepsPriorTTL(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	cache := NewLRUCache[string, string](2, 5*time.Second, func(key string) (string, bool) {
+		return "valueX", true
+	}, quietListener[string]{}, 24*time.Hour, WithClock[string, string](clock))
+	defer cache.Close()
+
+	cache.Put("keyX", "staleValue", time.Hour)
+	clock.Advance(2 * time.Hour)
+	if value := cache.Get("keyX"); value != "valueX" {
+		t.Errorf("Expected 'valueX' from backing store, got '%s'", value)
+	}
+	if ttl, found := cache.TTL("keyX"); !found || ttl != time.Hour {
+		t.Errorf("Expected the refilled entry to keep its '1h0m0s' TTL, got '%v'", ttl)
+	}
+}
+
+// Test Case 36: Fill TTL precedence: loader, prior entry, WithFillTTL, default
+func TestFillTTLPrecedence(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	cache, err := New(
+		WithDefaultTTL[string, string](time.Minute),
+		WithFillTTL[string, string](10*time.Minute),
+		WithListener[string, string](quietListener[string]{}),
+		WithCleanupInterval[string, string](24*time.Hour),
+		WithClock[string, string](clock),
+		WithLoaderTTL(func(key string) (string, time.Duration, error) {
+			if key == "chosen" {
+				return "value", 30 * time.Minute, nil
+			}
+			return "value", 0, nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+
+	cache.Put("prior", "old", 2*time.Hour)
+	clock.Advance(3 * time.Hour)
+	cache.Get("prior")
+	cache.Get("chosen")
+	cache.Get("fill")
+	cache.Put("manual", "value")
+
+	for key, expected := range map[string]time.Duration{
+		"chosen": 30 * time.Minute,
+		"prior":  2 * time.Hour,
+		"fill":   10 * time.Minute,
+		"manual": time.Minute,
+	} {
+		if ttl, found := cache.TTL(key); !found || ttl != expected {
+			t.Errorf("Expected '%v' for %s, got '%v' (found=%t)", expected, key, ttl, found)
+		}
+	}
+
+	if _, ttl, found := cache.GetWithTTL("other"); !found || ttl != 10*time.Minute {
+		t.Errorf("Expected GetWithTTL to report the fill TTL, got '%v'", ttl)
+	}
+}
+
+// Test Case 37: A Remove, Put, Clear or GetAndRemove during a load is not undone by the load
+func TestFillDoesNotResurrectRemovedKey(t *testing.T) {
+	writes := map[string]func(cache *LRUCache[string, string]){
+		"remove": func(cache *LRUCache[string, string]) { cache.Remove("key") },
+		"put":    func(cache *LRUCache[string, string]) { cache.Put("key", "fresh") },
+		"clear":  func(cache *LRUCache[string, string]) { cache.Clear() },
+		"take":   func(cache *LRUCache[string, string]) { cache.GetAndRemove("key") },
+	}
+	for name, write := range writes {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		cache := NewLRUCache[string, string](10, time.Minute, func(key string) (string, bool) {
+			close(started)
+			<-release
+			return "stale", true
+		}, quietListener[string]{}, time.Minute)
+
+		done := make(chan string)
+		go func() { done <- cache.Get("key") }()
+		<-started
+		write(cache)
+		close(release)
+		if value := <-done; value != "stale" {
+			t.Errorf("%s: Expected the caller to get 'stale', got '%s'", name, value)
+		}
+
+		expected := ""
+		if name == "put" {
+			expected = "fresh"
+		}
+		if value, _, _ := cache.GetStale("key"); value != expected {
+			t.Errorf("%s: Expected '%s' to be cached, got '%s'", name, expected, value)
+		}
+		cache.Close()
+	}
+}
+
+// Test Case 38: A TTL of zero means the entry never expires
+func TestZeroTTLNeverExpires(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	clock := clocktest.New(time.Now())
+	cache := NewLRUCache[string, string](10, time.Second, nil, listener, time.Minute,
+		WithClock[string, string](clock))
+	defer cache.Close()
+
+	cache.Put("forever", "value", 0)
+	cache.Put("brief", "value")
+	clock.Advance(24 * time.Hour)
+
+	if value := cache.Get("forever"); value != "value" {
+		t.Errorf("Expected 'value', got '%s'", value)
+	}
+	if ttl, found := cache.TTL("forever"); !found || ttl != 0 {
+		t.Errorf("Expected a zero TTL to be reported, got '%v' (found=%t)", ttl, found)
+	}
+	if value := cache.Get("brief"); value != "" {
+		t.Errorf("Expected '', got '%s'", value)
+	}
+	if value := listener.expireMap["forever"]; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 39: A zero default TTL makes entries permanent unless overridden
+func TestZeroDefaultTTL(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	cache, err := New(
+		WithClock[string, string](clock),
+		WithListener[string, string](quietListener[string]{}),
+		WithCleanupInterval[string, string](time.Second),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	cache.Put("permanent", "value")
+	cache.Put("brief", "value", time.Minute)
+	clock.Advance(time.Hour) // Runs the cleanup goroutine as well as Get.
+
+	if value := cache.Get("permanent"); value != "value" {
+		t.Errorf("Expected 'value', got '%s'", value)
+	}
+	if value := cache.Get("brief"); value != "" {
+		t.Errorf("Expected '', got '%s'", value)
+	}
+	if entries := cache.Entries(); len(entries) != 1 || !entries[0].ExpiresAt.IsZero() {
+		t.Errorf("Expected one entry with no expiry time, got %v", entries)
+	}
+}
+
+// Test Case 40: GetMultiOk partitions keys into found and missing
+func TestGetMultiOk(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestCache(4, 5*time.Second, listener).(*LRUCache[string, string])
+	defer cache.Close()
+
+	cache.Put("key1", "value1")
+	values, missing := cache.GetMultiOk([]string{"key1", "keyX", "absent", "key1"})
+
+	if len(values) != 2 || values["key1"] != "value1" || values["keyX"] != "valueX" {
+		t.Errorf("Expected key1 and keyX to be found, got %v", values)
+	}
+	if len(missing) != 1 || missing[0] != "absent" {
+		t.Errorf("Expected only 'absent' to be missing, got %v", missing)
+	}
+	if value := listener.hitMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := listener.missMap["absent"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 41: RemovePrefix drops exactly the keys under the prefix
+func TestRemovePrefix(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, listener, 5*time.Second)
+	defer cache.Close()
+
+	cache.Put("session:user123:cart", "value")
+	cache.Put("session:user123:prefs", "value")
+	cache.Put("session:user1234:cart", "value")
+	cache.Put("profile:user123", "value")
+
+	if removed := RemovePrefix(cache, "session:user123:"); removed != 2 {
+		t.Errorf("Expected '2', got '%d'", removed)
+	}
+	if value := cache.Len(); value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+	if !cache.Contains("session:user1234:cart") || !cache.Contains("profile:user123") {
+		t.Errorf("Expected the keys outside the prefix to stay, got %v", cache.Keys())
+	}
+	if value := listener.removeMap["session:user123:prefs"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 42: PutAndReturnOld reports the value it replaced
+func TestPutAndReturnOld(t *testing.T) {
+	cache := newTestCache(2, 5*time.Second, quietListener[string]{}).(*LRUCache[string, string])
+	defer cache.Close()
+
+	cache.Put("key1", "value1")
+	old, replaced := cache.PutAndReturnOld("key1", "value2")
+	if old != "value1" || !replaced {
+		t.Errorf("Expected 'value1' and true, got '%s' and %t", old, replaced)
+	}
+	if value := cache.Get("key1"); value != "value2" {
+		t.Errorf("Expected 'value2', got '%s'", value)
+	}
+
+	old, replaced = cache.PutAndReturnOld("key2", "value1")
+	if old != "" || replaced {
+		t.Errorf("Expected '' and false, got '%s' and %t", old, replaced)
+	}
+}
+
+// Test Case 43: ResetStats hands over the counters and starts them afresh
+func TestResetStats(t *testing.T) {
+	cache := newTestCache(2, 5*time.Second, quietListener[string]{}).(*LRUCache[string, string])
+	defer cache.Close()
+
+	cache.Put("key1", "value1")
+	cache.Get("key1")
+	cache.Get("missing")
+	if stats := cache.ResetStats(); stats.Hits != 1 || stats.Misses != 1 || stats.Size != 1 {
+		t.Errorf("Expected 1 hit, 1 miss and size 1, got %+v", stats)
+	}
+
+	cache.Get("key1")
+	cache.Get("key1")
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Misses != 0 || stats.Size != 1 {
+		t.Errorf("Expected 2 hits, 0 misses and size 1, got %+v", stats)
+	}
 }
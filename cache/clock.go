@@ -0,0 +1,30 @@
+package cache
+
+import "time"
+
+// Clock is the cache's source of time: entry timestamps, expiry checks and the
+// ticks of the cleanup goroutine all come from it. The default is the system
+// clock; tests can substitute a fake one, such as clocktest.FakeClock, with
+// WithClock to control expiry without sleeping.
+type Clock interface {
+	Now() time.Time
+	// NewTicker returns a channel that receives the time every d and a
+	// function that stops the ticks, like time.NewTicker.
+	NewTicker(d time.Duration) (<-chan time.Time, func())
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(d)
+	return ticker.C, ticker.Stop
+}
+
+// now returns the current time according to the cache's clock.
+func (c *LRUCache[K, V]) now() time.Time {
+	return c.options.clock.Now()
+}
@@ -0,0 +1,56 @@
+package cache
+
+// Clone returns an independent cache with the same configuration and a copy
+// of the current entries, with their eviction order, remaining TTLs, tags and
+// pins. The clone has its own lock, statistics and cleanup goroutine, and
+// must be closed separately. Values are copied with the WithValueCloner
+// cloner if there is one, and by assignment otherwise, in which case pointers
+// and slices are shared with the original. Policies that track more than order,
+// such as LFU counts or ARC ghosts, start afresh. The clone does not inherit
+// the original's destinations or peers: it saves neither to the
+// WithPersistence path nor any snapshot, spills nothing, as the spilled
+// entries stay with the original, and takes no part in the invalidation bus.
+func (c *LRUCache[K, V]) Clone() *LRUCache[K, V] {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	config := c.options
+	config.persistPath = ""
+	config.snapshotWriter = nil
+	config.snapshotPath = ""
+	config.spill = nil
+	config.invalidationBus = nil
+	clone := newLRUCache(c.capacity, c.defaultTTL, c.backingStore, c.cacheListener, c.cleanupInterval, func(o *options[K, V]) {
+		*o = config
+	})
+
+	var items []*CacheItem[K, V]
+	c.policy.entries(func(item *CacheItem[K, V]) bool {
+		items = append(items, item)
+		return true
+	})
+	// entries yields the entry to be evicted last first, so insert in reverse.
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+		value, ok := c.read(item)
+		if !ok {
+			continue
+		}
+		clone.put(item.key, value, item.expiry)
+		copied, found := clone.cache[item.key]
+		if !found {
+			continue
+		}
+		copied.timestamp.Store(item.timestamp.Load())
+		clone.tag(copied, item.tags)
+		clone.setPriority(copied, item.priority)
+		if item.pinned {
+			copied.pinned = true
+			copied.noExpiry = item.noExpiry
+			clone.pinned++
+		}
+	}
+
+	clone.start()
+	return clone
+}
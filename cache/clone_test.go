@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"io"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test Case 1: A clone copies entries in order and changes independently
+func TestClone(t *testing.T) {
+	cache := NewLRUCache[string, string](3, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2", 50*time.Millisecond)
+	cache.Put("key3", "value3")
+	cache.Get("key1")
+
+	clone := cache.Clone()
+	defer clone.Close()
+	if keys := clone.Keys(); !slices.Equal(keys, []string{"key1", "key3", "key2"}) {
+		t.Errorf("Expected [key1 key3 key2], got %v", keys)
+	}
+
+	clone.Put("key4", "value4") // Evicts key2 from the clone only
+	clone.Put("key1", "changed")
+	clone.Remove("key3")
+	if value := cache.Len(); value != 3 {
+		t.Errorf("Expected '3', got '%d'", value)
+	}
+	if value := cache.Get("key1"); value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+	if !cache.Contains("key2") || !cache.Contains("key3") {
+		t.Errorf("Expected the original to keep key2 and key3")
+	}
+	if value := clone.Len(); value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+}
+
+// Test Case 2: A clone keeps the remaining TTL rather than restarting it
+func TestCloneKeepsTTL(t *testing.T) {
+	cache := NewLRUCache[string, string](3, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+
+	cache.Put("key1", "value1", 40*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	clone := cache.Clone()
+	defer clone.Close()
+	time.Sleep(20 * time.Millisecond) // Past the original TTL
+	if clone.Contains("key1") {
+		t.Errorf("Expected 'key1' to have expired in the clone")
+	}
+}
+
+// Test Case 3: A clone's evictions and Close leave the original's spill store and snapshots alone
+func TestCloneKeepsOwnState(t *testing.T) {
+	store, err := NewDirSpill[string, string](t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	var snapshots atomic.Int32
+	cache, err := New(
+		WithCapacity[string, string](2),
+		WithCleanupInterval[string, string](24*time.Hour),
+		WithListener[string, string](quietListener[string]{}),
+		WithSpill[string, string](store),
+		WithPeriodicSnapshot[string, string](24*time.Hour, func() (io.Writer, error) {
+			snapshots.Add(1)
+			return io.Discard, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3") // Spills key1.
+	clone := cache.Clone()
+	clone.Put("key1", "cloned")
+	clone.Put("key4", "value4") // Evictions in the clone spill nothing.
+	clone.Put("key5", "value5")
+	clone.Close()
+
+	if value := snapshots.Load(); value != 0 {
+		t.Errorf("Expected the clone to write no snapshot, got %d", value)
+	}
+	if value := cache.Get("key1"); value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+	if value := cache.Get("key4"); value != "" {
+		t.Errorf("Expected nothing spilled by the clone, got '%s'", value)
+	}
+}
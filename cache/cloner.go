@@ -0,0 +1,39 @@
+package cache
+
+import "bytes"
+
+// CloneBytes is a value cloner for []byte values, for use with
+// WithValueCloner.
+func CloneBytes(value []byte) []byte {
+	return bytes.Clone(value)
+}
+
+// CloneMethod is a value cloner for types with a Clone method returning their
+// own type, such as a *T whose Clone returns a deep copy as a *T, for use with
+// WithValueCloner.
+func CloneMethod[V interface{ Clone() V }](value V) V {
+	return value.Clone()
+}
+
+// encode returns the form in which value is stored and whether it is
+// compressed. Compression already copies the value, so only an uncompressed
+// value is cloned for WithValueCloner's onPut.
+func (c *LRUCache[K, V]) encode(value V) (V, bool) {
+	if stored, compressed := c.compress(value); compressed {
+		return stored, true
+	}
+	if c.options.cloneOnPut {
+		value = c.options.cloneValue(value)
+	}
+	return value, false
+}
+
+// read returns the value of item for handing to a caller: a clone when
+// WithValueCloner is set, so that the caller cannot modify the stored value.
+func (c *LRUCache[K, V]) read(item *CacheItem[K, V]) (V, bool) {
+	value, ok := c.valueOf(item)
+	if ok && !item.compressed && c.options.cloneValue != nil {
+		value = c.options.cloneValue(value)
+	}
+	return value, ok
+}
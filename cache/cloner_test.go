@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+type profile struct {
+	Name string
+	Tags []string
+}
+
+func (p *profile) Clone() *profile {
+	clone := *p
+	clone.Tags = append([]string(nil), p.Tags...)
+	return &clone
+}
+
+// Test Case 1: Callers mutating what Get returns do not change the cached value
+func TestValueClonerOnRead(t *testing.T) {
+	cache := NewLRUCache[string, *profile](10, 5*time.Second, func(key string) (*profile, bool) {
+		return &profile{Name: key}, true
+	}, quietListener[string]{}, 5*time.Second, WithValueCloner[string, *profile](CloneMethod[*profile], false))
+	defer cache.Close()
+
+	stored := &profile{Name: "alice", Tags: []string{"admin"}}
+	cache.Put("alice", stored)
+	got := cache.Get("alice")
+	got.Name = "mallory"
+	got.Tags[0] = "root"
+	if value := cache.Get("alice"); value.Name != "alice" || value.Tags[0] != "admin" {
+		t.Errorf("Expected alice/admin, got %s/%s", value.Name, value.Tags[0])
+	}
+
+	// Without onPut the stored value is still the caller's pointer.
+	stored.Name = "changed"
+	if value := cache.Get("alice").Name; value != "changed" {
+		t.Errorf("Expected 'changed', got '%s'", value)
+	}
+
+	loaded := cache.Get("bob")
+	loaded.Name = "mallory"
+	if value := cache.Get("bob").Name; value != "bob" {
+		t.Errorf("Expected 'bob', got '%s'", value)
+	}
+}
+
+// Test Case 2: With onPut, mutating a value after storing it has no effect
+func TestValueClonerOnPut(t *testing.T) {
+	cache := NewLRUCache[string, []byte](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithValueCloner[string, []byte](CloneBytes, true))
+	defer cache.Close()
+
+	value := []byte("value")
+	cache.Put("key", value)
+	value[0] = 'X'
+	if got := string(cache.Get("key")); got != "value" {
+		t.Errorf("Expected 'value', got '%s'", got)
+	}
+}
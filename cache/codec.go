@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+// Codec encodes and decodes the entries of a snapshot.
+type Codec[K comparable, V any] interface {
+	Encode(w io.Writer, entries []PersistedEntry[K, V]) error
+	Decode(r io.Reader) ([]PersistedEntry[K, V], error)
+}
+
+// GobCodec encodes snapshots with encoding/gob, the format used by
+// WithPeriodicSnapshot. It is compact but readable only from Go.
+type GobCodec[K comparable, V any] struct{}
+
+func (GobCodec[K, V]) Encode(w io.Writer, entries []PersistedEntry[K, V]) error {
+	return gob.NewEncoder(w).Encode(entries)
+}
+
+func (GobCodec[K, V]) Decode(r io.Reader) ([]PersistedEntry[K, V], error) {
+	var entries []PersistedEntry[K, V]
+	err := gob.NewDecoder(r).Decode(&entries)
+	return entries, err
+}
+
+// JSONCodec encodes snapshots as a JSON array, which other languages and
+// people can read. Keys and values must round-trip through encoding/json.
+type JSONCodec[K comparable, V any] struct{}
+
+func (JSONCodec[K, V]) Encode(w io.Writer, entries []PersistedEntry[K, V]) error {
+	return json.NewEncoder(w).Encode(entries)
+}
+
+func (JSONCodec[K, V]) Decode(r io.Reader) ([]PersistedEntry[K, V], error) {
+	var entries []PersistedEntry[K, V]
+	err := json.NewDecoder(r).Decode(&entries)
+	return entries, err
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+var (
+	gzipWriters = sync.Pool{
+		New: func() any {
+			w, _ := gzip.NewWriterLevel(nil, gzip.BestSpeed)
+			return w
+		},
+	}
+	gzipReaders sync.Pool
+)
+
+// compress returns the form in which value is stored and whether that form is
+// compressed. Only values of type []byte longer than the WithCompression
+// threshold are compressed, and only when that makes them smaller.
+func (c *LRUCache[K, V]) compress(value V) (V, bool) {
+	if c.options.compressThreshold <= 0 {
+		return value, false
+	}
+	data, ok := any(value).([]byte)
+	if !ok || len(data) <= c.options.compressThreshold {
+		return value, false
+	}
+
+	var buf bytes.Buffer
+	w := gzipWriters.Get().(*gzip.Writer)
+	w.Reset(&buf)
+	_, err := w.Write(data)
+	if err == nil {
+		err = w.Close()
+	}
+	gzipWriters.Put(w)
+	if err != nil || buf.Len() >= len(data) {
+		return value, false
+	}
+	return any(bytes.Clone(buf.Bytes())).(V), true
+}
+
+// valueOf returns the value stored in item, decompressing it if needed. It
+// reports false if compressed data fails to decompress, which leaves the
+// entry unusable.
+func (c *LRUCache[K, V]) valueOf(item *CacheItem[K, V]) (V, bool) {
+	if !item.compressed {
+		return item.value, true
+	}
+	var zeroValue V
+	src := bytes.NewReader(any(item.value).([]byte))
+	r, _ := gzipReaders.Get().(*gzip.Reader)
+	var err error
+	if r == nil {
+		r, err = gzip.NewReader(src)
+	} else {
+		err = r.Reset(src)
+	}
+	if err != nil {
+		return zeroValue, false
+	}
+	data, err := io.ReadAll(r)
+	gzipReaders.Put(r)
+	if err != nil {
+		return zeroValue, false
+	}
+	return any(data).(V), true
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func bytesLength(key string, value []byte) int64 {
+	return int64(len(value))
+}
+
+// Test Case 1: Large values are stored compressed and read back unchanged
+func TestCompressionRoundTrip(t *testing.T) {
+	cache := NewLRUCache[string, []byte](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithCompression[string, []byte](64),
+		WithMaximumWeight[string, []byte](1<<20, bytesLength))
+	defer cache.Close()
+
+	large := bytes.Repeat([]byte(`{"name":"value","count":12345},`), 100)
+	small := []byte("short")
+	cache.Put("large", large)
+	cache.Put("small", small)
+
+	if value := cache.Get("large"); !bytes.Equal(value, large) {
+		t.Errorf("Expected the original %d bytes, got %d", len(large), len(value))
+	}
+	if value := cache.Get("small"); !bytes.Equal(value, small) {
+		t.Errorf("Expected '%s', got '%s'", small, value)
+	}
+	if value := cache.Weight(); value >= int64(len(large)/2) {
+		t.Errorf("Expected the compressed weight to be well below %d, got '%d'", len(large), value)
+	}
+
+	if !cache.CompareAndSwapFunc("large", large, small, bytes.Equal) {
+		t.Errorf("Expected the swap to compare against the original bytes")
+	}
+}
+
+// Test Case 2: Corrupted stored data is dropped rather than returned
+func TestCompressionCorruption(t *testing.T) {
+	cache := NewLRUCache[string, []byte](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithCompression[string, []byte](16))
+	defer cache.Close()
+
+	cache.Put("key", bytes.Repeat([]byte("a"), 1000))
+	item := cache.cache["key"]
+	if !item.compressed {
+		t.Fatalf("Expected the value to be stored compressed")
+	}
+	item.value = []byte("not gzip")
+
+	if _, found := cache.GetOk("key"); found {
+		t.Errorf("Expected corrupted data to read as a miss")
+	}
+	if value := cache.Len(); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
@@ -0,0 +1,51 @@
+package cache
+
+import "time"
+
+// Number is the set of types a CounterCache can hold.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// CounterCache is an LRUCache of numeric values that can be adjusted in place.
+type CounterCache[K comparable, V Number] struct {
+	*LRUCache[K, V]
+}
+
+// NewCounterCache takes the same arguments as NewLRUCache.
+func NewCounterCache[K comparable, V Number](capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration, opts ...Option[K, V]) *CounterCache[K, V] {
+	return &CounterCache[K, V]{NewLRUCache(capacity, defaultTTL, backingStore, cacheListener, cleanupInterval, opts...)}
+}
+
+// Increment atomically adds delta to the value stored for key and returns the
+// result. A missing or expired key starts from zero and is stored with the
+// default TTL; the backing store is not consulted.
+func (c *CounterCache[K, V]) Increment(key K, delta V) V {
+	key = c.normalize(key)
+	defer c.publishInvalidation(true, key)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	expiry := c.defaultTTL
+	var current V
+	if item, found := c.cache[key]; found {
+		if item.expiredAt(c.now()) {
+			c.removeItem(item)
+			c.onExpire(item)
+			c.releaseItem(item)
+		} else {
+			current = item.value
+			expiry = item.expiry
+		}
+	}
+	c.put(key, current+delta, expiry)
+	return current + delta
+}
+
+// Decrement atomically subtracts delta from the value stored for key and
+// returns the result, with the same rules as Increment.
+func (c *CounterCache[K, V]) Decrement(key K, delta V) V {
+	return c.Increment(key, -delta)
+}
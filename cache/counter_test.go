@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test Case 1: Missing keys start from zero
+func TestCounterIncrementDecrement(t *testing.T) {
+	cache := NewCounterCache[string, int](2, 5*time.Second, nil, NewCountingCacheListener[string](), 5*time.Second)
+
+	if value := cache.Increment("key1", 5); value != 5 {
+		t.Errorf("Expected '5', got '%d'", value)
+	}
+	if value := cache.Decrement("key1", 2); value != 3 {
+		t.Errorf("Expected '3', got '%d'", value)
+	}
+	if value := cache.Get("key1"); value != 3 {
+		t.Errorf("Expected '3', got '%d'", value)
+	}
+}
+
+// Test Case 2: An expired counter restarts from zero
+func TestCounterExpired(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewCounterCache[string, float64](2, 5*time.Second, nil, listener, 5*time.Second)
+
+	cache.Put("key1", 10, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond) // Wait for expiration
+	if value := cache.Increment("key1", 1.5); value != 1.5 {
+		t.Errorf("Expected '1.5', got '%f'", value)
+	}
+	if value := listener.expireMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 3: Concurrent increments are not lost
+func TestCounterConcurrentIncrements(t *testing.T) {
+	cache := NewCounterCache[string, int64](2, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				cache.Increment("key1", 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if value := cache.Get("key1"); value != 10000 {
+		t.Errorf("Expected '10000', got '%d'", value)
+	}
+}
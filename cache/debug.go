@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// DebugResponse is the JSON document served by DebugHandler.
+type DebugResponse[K comparable] struct {
+	Stats   CacheStats     `json:"stats"`
+	Entries []EntryInfo[K] `json:"entries,omitempty"`
+}
+
+// DebugHandler returns an http.Handler that serves the cache's statistics as
+// JSON, suitable for mounting at a path like /debug/cache. Entry metadata is
+// included when the request carries an "entries=true" query parameter.
+func DebugHandler[K comparable, V any](c *LRUCache[K, V]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := DebugResponse[K]{Stats: c.Stats()}
+		if withEntries, _ := strconv.ParseBool(r.URL.Query().Get("entries")); withEntries {
+			response.Entries = c.Entries()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
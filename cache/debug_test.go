@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func serveDebug(t *testing.T, cache *LRUCache[string, string], target string) DebugResponse[string] {
+	recorder := httptest.NewRecorder()
+	DebugHandler(cache).ServeHTTP(recorder, httptest.NewRequest("GET", target, nil))
+
+	var response DebugResponse[string]
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return response
+}
+
+// Test Case 1: Stats are served as JSON
+func TestDebugHandlerStats(t *testing.T) {
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, NewCountingCacheListener[string](), 5*time.Second)
+	defer cache.Close()
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3") // Evicts key1
+	cache.Get("key2")
+	cache.Get("key1")
+
+	response := serveDebug(t, cache, "/debug/cache")
+	expected := CacheStats{Hits: 1, Misses: 1, Evictions: 1, Size: 2, Capacity: 2}
+	if response.Stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, response.Stats)
+	}
+	if response.Entries != nil {
+		t.Errorf("Expected no entries, got %+v", response.Entries)
+	}
+}
+
+// Test Case 2: Entries are included on request
+func TestDebugHandlerEntries(t *testing.T) {
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, NewCountingCacheListener[string](), 5*time.Second)
+	defer cache.Close()
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+
+	response := serveDebug(t, cache, "/debug/cache?entries=true")
+	if len(response.Entries) != 2 {
+		t.Fatalf("Expected '2' entries, got '%d'", len(response.Entries))
+	}
+	if key := response.Entries[0].Key; key != "key2" {
+		t.Errorf("Expected 'key2' first, got '%s'", key)
+	}
+	if entry := response.Entries[1]; !entry.ExpiresAt.Equal(entry.LastAccess.Add(5 * time.Second)) {
+		t.Errorf("Expected expiry 5s after last access, got %+v", entry)
+	}
+}
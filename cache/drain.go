@@ -0,0 +1,18 @@
+package cache
+
+// ExpiredEntry is an expired key and value delivered to the channel given to
+// WithDrainExpired.
+type ExpiredEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// DrainPolicy decides what WithDrainExpired does when its channel is full.
+type DrainPolicy int
+
+const (
+	// DrainDrop drops the entry and counts it in Stats as a drain drop.
+	DrainDrop DrainPolicy = iota
+	// DrainBlock waits for the consumer to make room.
+	DrainBlock
+)
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// Test Case 1: Lazily and background expired entries reach the channel with their values
+func TestDrainExpired(t *testing.T) {
+	expired := make(chan ExpiredEntry[string, string], 10)
+	cache := NewLRUCache[string, string](5, 5*time.Second, nil, quietListener[string]{}, 50*time.Millisecond,
+		WithDrainExpired[string, string](expired, DrainBlock))
+	defer cache.Close()
+
+	cache.Put("key1", "handle1", 10*time.Millisecond)
+	cache.Put("key2", "handle2", 10*time.Millisecond)
+	cache.Put("key3", "handle3")
+	time.Sleep(20 * time.Millisecond) // Wait for expiration
+	cache.Get("key1")
+
+	if entry := <-expired; entry.Key != "key1" || entry.Value != "handle1" {
+		t.Errorf("Expected key1=handle1, got %v", entry)
+	}
+	select {
+	case entry := <-expired:
+		if entry.Key != "key2" || entry.Value != "handle2" {
+			t.Errorf("Expected key2=handle2, got %v", entry)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected 'key2' to be reaped by the cleanup goroutine")
+	}
+
+	cache.Remove("key3")
+	select {
+	case entry := <-expired:
+		t.Errorf("Expected removed entries not to be drained, got %v", entry)
+	default:
+	}
+}
+
+// Test Case 2: A full channel drops entries under DrainDrop
+func TestDrainExpiredDrop(t *testing.T) {
+	expired := make(chan ExpiredEntry[string, string], 1)
+	cache := NewLRUCache[string, string](5, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithDrainExpired[string, string](expired, DrainDrop))
+
+	cache.Put("key1", "value1", 10*time.Millisecond)
+	cache.Put("key2", "value2", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond) // Wait for expiration
+	cache.Get("key1")
+	cache.Get("key2")
+
+	if entry := <-expired; entry.Key != "key1" {
+		t.Errorf("Expected 'key1', got '%s'", entry.Key)
+	}
+	if value := cache.Stats().DrainDrops; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
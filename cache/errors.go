@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrValueTooLarge is returned by PutE for a value heavier than the maximum
+// weight of the cache.
+var ErrValueTooLarge = errors.New("cache: value exceeds the maximum weight")
+
+// ErrNotFound is returned when a key is neither in the cache nor in its
+// backing store. A WithLoaderE loader returns it, or an error wrapping it, to
+// report a key that does not exist.
+var ErrNotFound = errors.New("cache: key not found")
+
+// ErrDoNotCache is returned by a WithLoaderE, WithLoaderContext or
+// WithLoaderTTL loader, possibly wrapped, along with a value that is a valid
+// answer but should not be kept, such as a degraded response served while an
+// upstream recovers. The read that caused the load gets the value without an
+// error, nothing is stored, and the next read of the key loads it again. An
+// entry that Refresh or WithRefreshAhead was reloading is left as it was.
+var ErrDoNotCache = errors.New("cache: do not cache the value")
+
+// ErrClosed is returned by operations attempted after Close.
+var ErrClosed = errors.New("cache: closed")
+
+// ErrLoadCycle is returned by GetContext for a key whose own load is already
+// in progress further up the same chain of loads, which could otherwise
+// recurse forever.
+var ErrLoadCycle = errors.New("cache: load depends on itself")
+
+// ErrLoadFailed matches every *LoadError under errors.Is, for callers that do
+// not need the key or the cause.
+var ErrLoadFailed = errors.New("cache: load failed")
+
+// LoadError reports that loading Key from the backing store failed, because
+// the loader returned Err or because it did not finish within the load
+// timeout, in which case Err is context.DeadlineExceeded. It unwraps to Err
+// and also matches ErrLoadFailed.
+type LoadError struct {
+	Key any
+	Err error
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("cache: loading key %v: %v", e.Key, e.Err)
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+func (e *LoadError) Is(target error) bool {
+	return target == ErrLoadFailed
+}
@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vivekkothari/in-memory-cache/cache/testutil/clocktest"
+)
+
+var errDatabaseDown = errors.New("database down")
+
+func newFallibleCache(loader func(string) (string, error), opts ...Option[string, string]) *LRUCache[string, string] {
+	opts = append([]Option[string, string]{
+		WithDefaultTTL[string, string](5 * time.Second),
+		WithLoaderE(loader),
+		WithListener[string, string](quietListener[string]{}),
+	}, opts...)
+	cache, err := New(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return cache
+}
+
+// Test Case 1: GetE tells a missing key from a failed load
+func TestGetEErrors(t *testing.T) {
+	cache := newFallibleCache(func(key string) (string, error) {
+		switch key {
+		case "present":
+			return "value", nil
+		case "absent":
+			return "", fmt.Errorf("no row for %s: %w", key, ErrNotFound)
+		default:
+			return "", errDatabaseDown
+		}
+	})
+	defer cache.Close()
+
+	if value, err := cache.GetE("present"); value != "value" || err != nil {
+		t.Errorf("Expected 'value', got '%s' and %v", value, err)
+	}
+	if _, err := cache.GetE("absent"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	_, err := cache.GetE("broken")
+	if !errors.Is(err, ErrLoadFailed) || !errors.Is(err, errDatabaseDown) {
+		t.Errorf("Expected a load failure caused by errDatabaseDown, got %v", err)
+	}
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || loadErr.Key != "broken" {
+		t.Errorf("Expected a *LoadError for 'broken', got %v", err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a failed load not to match ErrNotFound")
+	}
+	if value, found := cache.GetOk("broken"); value != "" || found {
+		t.Errorf("Expected GetOk to report a failed load as not found, got '%s'", value)
+	}
+}
+
+// Test Case 2: A timed-out load is a LoadError wrapping context.DeadlineExceeded
+func TestGetELoadTimeout(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	cache := newFallibleCache(func(key string) (string, error) {
+		close(started)
+		<-release
+		return "late", nil
+	}, WithLoadTimeout[string, string](time.Second), WithClock[string, string](clock))
+	defer cache.Close()
+
+	errs := make(chan error)
+	go func() {
+		_, err := cache.GetE("key")
+		errs <- err
+	}()
+	<-started
+	clock.Advance(time.Second)
+	err := <-errs
+	if !errors.Is(err, ErrLoadFailed) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a load failure caused by the deadline, got %v", err)
+	}
+}
+
+// Test Case 3: Failed loads are retried, missing keys are remembered
+func TestFailedLoadNotNegativelyCached(t *testing.T) {
+	calls := 0
+	cache := newFallibleCache(func(key string) (string, error) {
+		calls++
+		if key == "absent" {
+			return "", ErrNotFound
+		}
+		return "", errDatabaseDown
+	}, WithNegativeCache[string, string](10, time.Minute))
+	defer cache.Close()
+
+	cache.GetE("broken")
+	cache.GetE("broken")
+	if calls != 2 {
+		t.Errorf("Expected a failed load to be retried, got '%d' calls", calls)
+	}
+	cache.GetE("absent")
+	if _, err := cache.GetE("absent"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected the missing key to be remembered, got '%d' calls", calls)
+	}
+}
+
+// Test Case 4: Operations after Close report ErrClosed
+func TestErrClosed(t *testing.T) {
+	cache := newFallibleCache(func(key string) (string, error) { return "value", nil })
+	cache.Close()
+
+	if _, err := cache.GetE("key"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed from GetE, got %v", err)
+	}
+	if err := cache.PutE("key", "value"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed from PutE, got %v", err)
+	}
+
+	sharded := NewShardedLRUCache[string, string](2, 10, time.Second, nil, quietListener[string]{}, time.Second)
+	sharded.Close()
+	if _, err := sharded.GetE("key"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed from a sharded GetE, got %v", err)
+	}
+}
+
+// Test Case 5: A value returned with ErrDoNotCache is served but not stored
+func TestLoaderDoNotCache(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	calls := 0
+	cache := newFallibleCache(func(key string) (string, error) {
+		calls++
+		if calls <= 2 {
+			return "degraded", fmt.Errorf("upstream recovering: %w", ErrDoNotCache)
+		}
+		return "value", nil
+	}, WithCapacity[string, string](1), WithListener[string, string](listener))
+	defer cache.Close()
+
+	cache.Put("other", "value")
+	if value, err := cache.GetE("key"); value != "degraded" || err != nil {
+		t.Errorf("Expected 'degraded' and no error, got '%s' and '%v'", value, err)
+	}
+	if cache.Contains("key") || !cache.Contains("other") {
+		t.Errorf("Expected the degraded value to be left out, and nothing evicted for it")
+	}
+	if value := cache.Get("key"); value != "degraded" {
+		t.Errorf("Expected 'degraded', got '%s'", value)
+	}
+	if value := cache.Get("key"); value != "value" {
+		t.Errorf("Expected 'value', got '%s'", value)
+	}
+	if calls != 3 {
+		t.Errorf("Expected every read to consult the loader until it allows caching, got %d calls", calls)
+	}
+	if len(listener.expireMap) != 0 || listener.evictMap["key"] != 0 {
+		t.Errorf("Expected no expirations and no eviction of key, got %v and %v", listener.expireMap, listener.evictMap)
+	}
+}
@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of a CacheEvent.
+type EventType int
+
+const (
+	EventHit EventType = iota
+	EventMiss
+	EventLoad
+	EventEvict
+	EventExpire
+	EventRemove
+	EventReject
+	EventRefresh
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventHit:
+		return "hit"
+	case EventMiss:
+		return "miss"
+	case EventLoad:
+		return "load"
+	case EventEvict:
+		return "evict"
+	case EventExpire:
+		return "expire"
+	case EventRemove:
+		return "remove"
+	case EventReject:
+		return "reject"
+	case EventRefresh:
+		return "refresh"
+	default:
+		return "unknown"
+	}
+}
+
+// CacheEvent is an event delivered to a Subscribe channel.
+type CacheEvent[K comparable] struct {
+	Type EventType
+	Key  K
+	Time time.Time
+	// Loader and Latency describe an EventLoad: the WithLoaderFor route the
+	// key was loaded by, numbered from 1 in the order the routes were added,
+	// or zero if another loader loaded it, it came back from the spill store
+	// or the read was served another read's load; and how long the read
+	// waited for the load.
+	Loader  int
+	Latency time.Duration
+}
+
+// subscriberBuffer is the capacity of each Subscribe channel.
+const subscriberBuffer = 256
+
+type subscription[K comparable] struct {
+	types  []EventType
+	mutex  sync.Mutex
+	events chan CacheEvent[K]
+	closed bool
+}
+
+// deliver sends event without blocking, dropping it if the channel is full.
+func (s *subscription[K]) deliver(event CacheEvent[K]) {
+	if !slices.Contains(s.types, event.Type) {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.events <- event:
+	default:
+	}
+}
+
+func (s *subscription[K]) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+}
+
+// Subscribe returns a channel that receives the events of the given types, or
+// of every type if none are given, together with a function that stops the
+// delivery and closes the channel; Close closes it too. Each subscriber has
+// its own channel. Events are sent without blocking the cache, so a
+// subscriber that falls more than a buffer's worth behind misses events.
+func (c *LRUCache[K, V]) Subscribe(types ...EventType) (<-chan CacheEvent[K], func()) {
+	if len(types) == 0 {
+		types = []EventType{EventHit, EventMiss, EventLoad, EventEvict, EventExpire, EventRemove, EventReject, EventRefresh}
+	}
+	sub := &subscription[K]{
+		types:  types,
+		events: make(chan CacheEvent[K], subscriberBuffer),
+	}
+
+	c.subscribersMutex.Lock()
+	subscribers := append(slices.Clone(c.loadSubscribers()), sub)
+	c.subscribers.Store(&subscribers)
+	c.subscribersMutex.Unlock()
+
+	unsubscribe := func() {
+		c.subscribersMutex.Lock()
+		subscribers := slices.DeleteFunc(slices.Clone(c.loadSubscribers()), func(s *subscription[K]) bool {
+			return s == sub
+		})
+		c.subscribers.Store(&subscribers)
+		c.subscribersMutex.Unlock()
+		sub.close()
+	}
+	return sub.events, unsubscribe
+}
+
+func (c *LRUCache[K, V]) loadSubscribers() []*subscription[K] {
+	if subscribers := c.subscribers.Load(); subscribers != nil {
+		return *subscribers
+	}
+	return nil
+}
+
+// publish delivers an event to the subscribers interested in its type.
+func (c *LRUCache[K, V]) publish(eventType EventType, key K) {
+	subscribers := c.loadSubscribers()
+	if len(subscribers) == 0 {
+		return
+	}
+	event := CacheEvent[K]{Type: eventType, Key: key, Time: c.now()}
+	for _, sub := range subscribers {
+		sub.deliver(event)
+	}
+}
+
+// publishLoad delivers the EventLoad of key, which route loaded in latency.
+func (c *LRUCache[K, V]) publishLoad(key K, route int, latency time.Duration) {
+	subscribers := c.loadSubscribers()
+	if len(subscribers) == 0 {
+		return
+	}
+	event := CacheEvent[K]{Type: EventLoad, Key: key, Time: c.now(), Loader: route, Latency: latency}
+	for _, sub := range subscribers {
+		sub.deliver(event)
+	}
+}
+
+// closeSubscribers closes every subscriber's channel.
+func (c *LRUCache[K, V]) closeSubscribers() {
+	c.subscribersMutex.Lock()
+	subscribers := c.loadSubscribers()
+	c.subscribers.Store(nil)
+	c.subscribersMutex.Unlock()
+	for _, sub := range subscribers {
+		sub.close()
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func drainEvents[K comparable](events <-chan CacheEvent[K]) []CacheEvent[K] {
+	var drained []CacheEvent[K]
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return drained
+			}
+			drained = append(drained, event)
+		default:
+			return drained
+		}
+	}
+}
+
+// Test Case 1: Each subscriber receives only the event types it asked for
+func TestSubscribeFiltersByType(t *testing.T) {
+	cache := NewLRUCache[string, string](1, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+
+	evictions, stopEvictions := cache.Subscribe(EventEvict)
+	defer stopEvictions()
+	misses, stopMisses := cache.Subscribe(EventMiss)
+	defer stopMisses()
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2") // Evicts key1
+	cache.Get("key2")
+	cache.Get("key1")
+
+	if events := drainEvents(evictions); len(events) != 1 || events[0].Type != EventEvict || events[0].Key != "key1" {
+		t.Errorf("Expected one eviction of key1, got %+v", events)
+	}
+	if events := drainEvents(misses); len(events) != 1 || events[0].Type != EventMiss || events[0].Key != "key1" {
+		t.Errorf("Expected one miss of key1, got %+v", events)
+	}
+}
+
+// Test Case 2: Unsubscribing stops delivery and closes the channel
+func TestUnsubscribe(t *testing.T) {
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+
+	events, unsubscribe := cache.Subscribe()
+	other, _ := cache.Subscribe(EventRemove)
+	cache.Put("key1", "value1")
+	cache.Remove("key1")
+	unsubscribe()
+	unsubscribe() // Safe to call twice
+	cache.Put("key2", "value2")
+	cache.Remove("key2")
+
+	received := drainEvents(events)
+	if len(received) != 1 || received[0].Type != EventRemove {
+		t.Errorf("Expected only the remove before unsubscribing, got %+v", received)
+	}
+	if _, open := <-events; open {
+		t.Errorf("Expected the channel to be closed")
+	}
+	if received := drainEvents(other); len(received) != 2 {
+		t.Errorf("Expected the other subscriber to see both removes, got %+v", received)
+	}
+
+	cache.Close()
+	if _, open := <-other; open {
+		t.Errorf("Expected Close to close the remaining channels")
+	}
+}
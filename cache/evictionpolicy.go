@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"container/list"
+	"math/rand/v2"
+)
+
+// EvictionPolicy decides which key a cache evicts when it is full. Install one
+// with WithEvictionPolicy to experiment with orderings the built-in policies
+// do not cover.
+//
+// The cache calls every method with its write lock held, one call at a time,
+// so an implementation used by a single cache needs no locking of its own.
+// Because hits must reach OnAccess under that lock, a cache with a custom
+// policy never serves hits under the read lock. Methods must not call back
+// into the cache.
+type EvictionPolicy[K comparable] interface {
+	// OnInsert is called when key is added to the cache.
+	OnInsert(key K)
+	// OnAccess is called when key is read, or overwritten by Put.
+	OnAccess(key K)
+	// OnRemove is called when key leaves the cache for any reason: eviction,
+	// expiry, Remove or Clear.
+	OnRemove(key K)
+	// Victim returns the key to evict next, or false if there is none. It must
+	// not forget the key; OnRemove follows if the key is actually evicted. If
+	// the victim is pinned or WithCanEvict vetoes it, the cache evicts
+	// another key instead, in no particular order.
+	Victim() (K, bool)
+}
+
+// customPolicy adapts an EvictionPolicy to the cache's internal policy
+// interface. items points at the cache's map, which Clear replaces.
+type customPolicy[K comparable, V any] struct {
+	policy EvictionPolicy[K]
+	items  *map[K]*CacheItem[K, V]
+}
+
+func (p *customPolicy[K, V]) add(item *CacheItem[K, V]) {
+	p.policy.OnInsert(item.key)
+}
+
+func (p *customPolicy[K, V]) touch(item *CacheItem[K, V]) {
+	p.policy.OnAccess(item.key)
+}
+
+func (p *customPolicy[K, V]) sharedHits() bool {
+	return false
+}
+
+func (p *customPolicy[K, V]) touchShared(*CacheItem[K, V]) {}
+
+func (p *customPolicy[K, V]) remove(item *CacheItem[K, V]) {
+	p.policy.OnRemove(item.key)
+}
+
+// victims yields the policy's victim first and then, in case the cache
+// passes over it because it is pinned or vetoed, the other items in map order,
+// so that the cache still finds something to evict.
+func (p *customPolicy[K, V]) victims(yield func(*CacheItem[K, V]) bool) {
+	var first *CacheItem[K, V]
+	if key, ok := p.policy.Victim(); ok {
+		if item, found := (*p.items)[key]; found {
+			if !yield(item) {
+				return
+			}
+			first = item
+		}
+	}
+	for _, item := range *p.items {
+		if item != first && !yield(item) {
+			return
+		}
+	}
+}
+
+// entries reports items in map order, since an EvictionPolicy does not expose
+// its ordering.
+func (p *customPolicy[K, V]) entries(yield func(*CacheItem[K, V]) bool) {
+	for _, item := range *p.items {
+		if !yield(item) {
+			return
+		}
+	}
+}
+
+// clear is called before the cache drops its map, so every key can still be
+// reported to OnRemove.
+func (p *customPolicy[K, V]) clear() {
+	for key := range *p.items {
+		p.policy.OnRemove(key)
+	}
+}
+
+// keyOrder is a list of keys with an index for O(1) lookups, shared by the
+// list-based EvictionPolicy implementations.
+type keyOrder[K comparable] struct {
+	order    *list.List
+	elements map[K]*list.Element
+}
+
+func newKeyOrder[K comparable]() keyOrder[K] {
+	return keyOrder[K]{order: list.New(), elements: make(map[K]*list.Element)}
+}
+
+func (o keyOrder[K]) OnInsert(key K) {
+	o.elements[key] = o.order.PushFront(key)
+}
+
+func (o keyOrder[K]) OnRemove(key K) {
+	if element, found := o.elements[key]; found {
+		o.order.Remove(element)
+		delete(o.elements, key)
+	}
+}
+
+func (o keyOrder[K]) Victim() (K, bool) {
+	if back := o.order.Back(); back != nil {
+		return back.Value.(K), true
+	}
+	var zeroKey K
+	return zeroKey, false
+}
+
+type lruEvictionPolicy[K comparable] struct {
+	keyOrder[K]
+}
+
+// NewLRUPolicy returns an EvictionPolicy that evicts the least recently used
+// key.
+func NewLRUPolicy[K comparable]() EvictionPolicy[K] {
+	return lruEvictionPolicy[K]{newKeyOrder[K]()}
+}
+
+func (p lruEvictionPolicy[K]) OnAccess(key K) {
+	if element, found := p.elements[key]; found {
+		p.order.MoveToFront(element)
+	}
+}
+
+type fifoEvictionPolicy[K comparable] struct {
+	keyOrder[K]
+}
+
+// NewFIFOPolicy returns an EvictionPolicy that evicts keys in insertion order.
+func NewFIFOPolicy[K comparable]() EvictionPolicy[K] {
+	return fifoEvictionPolicy[K]{newKeyOrder[K]()}
+}
+
+func (p fifoEvictionPolicy[K]) OnAccess(K) {}
+
+type randomEvictionPolicy[K comparable] struct {
+	keys    []K
+	indexes map[K]int
+}
+
+// NewRandomPolicy returns an EvictionPolicy that evicts a uniformly random key.
+func NewRandomPolicy[K comparable]() EvictionPolicy[K] {
+	return &randomEvictionPolicy[K]{indexes: make(map[K]int)}
+}
+
+func (p *randomEvictionPolicy[K]) OnInsert(key K) {
+	p.indexes[key] = len(p.keys)
+	p.keys = append(p.keys, key)
+}
+
+func (p *randomEvictionPolicy[K]) OnAccess(K) {}
+
+// OnRemove moves the last key into the removed key's slot.
+func (p *randomEvictionPolicy[K]) OnRemove(key K) {
+	index, found := p.indexes[key]
+	if !found {
+		return
+	}
+	last := len(p.keys) - 1
+	p.keys[index] = p.keys[last]
+	p.indexes[p.keys[index]] = index
+	var zeroKey K
+	p.keys[last] = zeroKey
+	p.keys = p.keys[:last]
+	delete(p.indexes, key)
+}
+
+func (p *randomEvictionPolicy[K]) Victim() (K, bool) {
+	if len(p.keys) == 0 {
+		var zeroKey K
+		return zeroKey, false
+	}
+	return p.keys[rand.IntN(len(p.keys))], true
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// costPolicy evicts the key with the lowest cost, as an example of a
+// domain-specific policy.
+type costPolicy struct {
+	costs map[string]int
+	live  map[string]bool
+}
+
+func (p *costPolicy) OnInsert(key string) { p.live[key] = true }
+func (p *costPolicy) OnAccess(string)     {}
+func (p *costPolicy) OnRemove(key string) { delete(p.live, key) }
+
+func (p *costPolicy) Victim() (string, bool) {
+	victim, found := "", false
+	for key := range p.live {
+		if !found || p.costs[key] < p.costs[victim] {
+			victim, found = key, true
+		}
+	}
+	return victim, found
+}
+
+// Test Case 1: NewLRUPolicy evicts like the built-in LRU
+func TestLRUEvictionPolicy(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second,
+		WithEvictionPolicy[string, string](NewLRUPolicy[string]))
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Get("key1")
+	cache.Put("key3", "value3")
+	if value := listener.evictMap["key2"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Get("key1"); value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+}
+
+// Test Case 2: NewFIFOPolicy ignores accesses
+func TestFIFOEvictionPolicy(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second,
+		WithEvictionPolicy[string, string](NewFIFOPolicy[string]))
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Get("key1")
+	cache.Put("key3", "value3")
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 3: NewRandomPolicy keeps the cache within capacity
+func TestRandomEvictionPolicy(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, listener, 5*time.Second,
+		WithEvictionPolicy[string, string](NewRandomPolicy[string]))
+
+	for i := 0; i < 100; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+		if i%3 == 0 {
+			cache.Remove(fmt.Sprint("key", i-1))
+		}
+	}
+	cache.Put("last", "value")
+	if value := cache.Len(); value != 10 {
+		t.Errorf("Expected '10', got '%d'", value)
+	}
+	policy := cache.policy.(*customPolicy[string, string]).policy.(*randomEvictionPolicy[string])
+	if value := len(policy.keys); value != 10 {
+		t.Errorf("Expected '10', got '%d'", value)
+	}
+	for key, index := range policy.indexes {
+		if policy.keys[index] != key {
+			t.Errorf("Expected '%s' at index %d, got '%s'", key, index, policy.keys[index])
+		}
+	}
+}
+
+// Test Case 4: A custom policy chooses the victim; expiry and Clear reach OnRemove
+func TestCustomEvictionPolicy(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	policy := &costPolicy{costs: map[string]int{"cheap": 1, "dear": 10, "new": 5}, live: map[string]bool{}}
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second,
+		WithEvictionPolicy[string, string](func() EvictionPolicy[string] { return policy }))
+
+	cache.Put("dear", "value")
+	cache.Put("cheap", "value")
+	cache.Get("cheap")
+	cache.Put("new", "value")
+	if value := listener.evictMap["cheap"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+
+	cache.Put("short", "value", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond) // Wait for expiration
+	cache.Get("short")
+	if policy.live["short"] {
+		t.Errorf("Expected 'short' to be removed from the policy")
+	}
+
+	cache.Clear()
+	if value := len(policy.live); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 5: A pinned custom victim makes way for another entry
+func TestCustomEvictionPolicyPinnedVictim(t *testing.T) {
+	policy := &costPolicy{costs: map[string]int{"cheap": 1, "dear": 10, "new": 5}, live: map[string]bool{}}
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithEvictionPolicy[string, string](func() EvictionPolicy[string] { return policy }))
+	defer cache.Close()
+
+	cache.Put("dear", "value")
+	cache.Put("cheap", "value")
+	cache.Pin("cheap")
+	cache.Put("new", "value")
+	cache.Put("newer", "value")
+	if value := cache.Len(); value > 2 {
+		t.Errorf("Expected at most 2 entries, got %d", value)
+	}
+	if !cache.Contains("cheap") {
+		t.Errorf("Expected the pinned victim to stay")
+	}
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestFIFOCache(capacity int, defaultTTL time.Duration, listener CacheListener[string]) *LRUCache[string, string] {
+	backingStore := func(key string) (string, bool) {
+		if key == "keyX" {
+			return "valueX", true
+		}
+		return "", false
+	}
+	return NewFIFOCache[string, string](capacity, defaultTTL, backingStore, listener, 5*time.Second)
+}
+
+// Test Case 1: Add and Retrieve
+func TestFIFOPutGet(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestFIFOCache(2, 5*time.Second, listener)
+
+	cache.Put("key1", "value1")
+	if value := cache.Get("key1"); value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+	if value := listener.hitMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 2: Retrieve Non-existent Key
+func TestFIFOGetNonExistentKey(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestFIFOCache(2, 5*time.Second, listener)
+
+	if value := cache.Get("keyX"); value != "valueX" { // Comes from backing store
+		t.Errorf("Expected 'valueX', got '%s'", value)
+	}
+	if value := cache.Get("keyY"); value != "" { // Not in backing store
+		t.Errorf("Expected '', got '%s'", value)
+	}
+	if value := listener.missMap["keyY"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 3: Updating a key keeps its position
+func TestFIFOUpdateKeepsPosition(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestFIFOCache(2, 5*time.Second, listener)
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key1", "value1b")
+	cache.Put("key3", "value3") // Still evicts key1, the first inserted
+
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Get("key2"); value != "value2" {
+		t.Errorf("Expected 'value2', got '%s'", value)
+	}
+}
+
+// Test Case 4: Remove Key
+func TestFIFORemove(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestFIFOCache(2, 5*time.Second, listener)
+
+	cache.Put("key1", "value1")
+	cache.Remove("key1")
+
+	if value := cache.Get("key1"); value != "" {
+		t.Errorf("Expected '', got '%s'", value)
+	}
+}
+
+// Test Case 5: Reads do not protect an entry from eviction
+func TestFIFOEviction(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestFIFOCache(3, 5*time.Second, listener)
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3")
+	cache.Get("key1")
+	cache.Get("key1")
+	cache.Put("key4", "value4") // Evicts key1 even though it was just read
+
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"key4", "key3", "key2"}) {
+		t.Errorf("Expected [key4 key3 key2], got %v", keys)
+	}
+}
+
+// Test Case 6: Expiration of Cached Items
+func TestFIFOExpiration(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestFIFOCache(2, 5*time.Second, listener)
+
+	cache.Put("key1", "value1", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond) // Wait for expiration
+
+	if value := cache.Get("key1"); value != "" {
+		t.Errorf("Expected '', got '%s'", value)
+	}
+	if value := listener.expireMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 7: Concurrent readers share the read lock, meant to be run with -race
+func TestFIFOConcurrentAccess(t *testing.T) {
+	cache := NewFIFOCache[string, string](50, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := fmt.Sprint("key", (g+i)%60)
+				if i%10 == 0 {
+					cache.Put(key, "value")
+				} else {
+					cache.Get(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if value := cache.Len(); value > 50 {
+		t.Errorf("Expected at most '50', got '%d'", value)
+	}
+	if err := cache.checkInvariants(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// fill tracks the backing-store loads of one key in flight, so that a write
+// or removal of the key meanwhile can stop them from caching what they
+// loaded, which by then may be stale. It also holds the reloads and reads of
+// the key that concurrent callers share.
+type fill[V any] struct {
+	loads int
+	stale bool
+	// reload is the Refresh or refresh-ahead reload of the key in flight, or
+	// under WithLockStripes its miss, and read the GetOrLoadNeg read.
+	reload *sharedLoad[V]
+	read   *sharedLoad[V]
+}
+
+// sharedLoad is a load whose outcome the callers that join it share. waiters
+// counts the callers that joined it.
+type sharedLoad[V any] struct {
+	done    chan struct{}
+	value   V
+	err     error
+	waiters int
+}
+
+// beginFill records that a load of key is starting.
+func (c *LRUCache[K, V]) beginFill(key K) *fill[V] {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.addFill(key)
+}
+
+// addFill is beginFill for a caller that holds the write lock.
+func (c *LRUCache[K, V]) addFill(key K) *fill[V] {
+	if c.fills == nil {
+		c.fills = make(map[K]*fill[V])
+	}
+	f, found := c.fills[key]
+	if !found {
+		f = &fill[V]{}
+		c.fills[key] = f
+	}
+	f.loads++
+	return f
+}
+
+// share runs load for key, unless the load in the slot of key's fill picked
+// by slot is already in flight, in which case it waits for that one and
+// returns its outcome instead. It reports whether load ran.
+func (c *LRUCache[K, V]) share(key K, slot func(*fill[V]) **sharedLoad[V], load func() (V, error)) (V, error, bool) {
+	c.mutex.Lock()
+	if f, found := c.fills[key]; found {
+		if s := *slot(f); s != nil {
+			s.waiters++
+			c.mutex.Unlock()
+			<-s.done
+			return s.value, s.err, false
+		}
+	}
+	f := c.addFill(key)
+	s := &sharedLoad[V]{done: make(chan struct{})}
+	*slot(f) = s
+	c.mutex.Unlock()
+
+	defer func() {
+		c.mutex.Lock()
+		*slot(f) = nil
+		c.finishFill(key, f)
+		c.mutex.Unlock()
+		close(s.done)
+	}()
+	s.value, s.err = load()
+	return s.value, s.err, true
+}
+
+// endFill caches the outcome of the load f of key: the value with ttl, or
+// the key's absence in negative, if not nil. Nothing is cached if the key was
+// stored, removed or cleared since the load began, so that a slow load cannot
+// bring back data that was invalidated while it ran.
+func (c *LRUCache[K, V]) endFill(key K, f *fill[V], value V, ttl time.Duration, err error, negative *LRUCache[K, struct{}]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.finishFill(key, f) {
+		return
+	}
+	switch {
+	case err == nil && c.options.cacheBackingResults:
+		c.put(key, value, ttl)
+	case negative != nil && errors.Is(err, ErrNotFound):
+		// Only a definite answer is remembered; a failed load may succeed
+		// next time.
+		negative.Put(key, struct{}{})
+	}
+}
+
+// finishFill records that the load f of key is done and reports whether
+// what it loaded may still be cached. The caller must hold the write lock.
+func (c *LRUCache[K, V]) finishFill(key K, f *fill[V]) bool {
+	if f.loads--; f.loads == 0 {
+		delete(c.fills, key)
+	}
+	return !f.stale
+}
+
+// invalidateFill marks the loads of key in flight as stale. The caller must
+// hold the write lock.
+func (c *LRUCache[K, V]) invalidateFill(key K) {
+	if f, found := c.fills[key]; found {
+		f.stale = true
+	}
+}
+
+// invalidateFills marks every load in flight as stale. The caller must hold
+// the write lock.
+func (c *LRUCache[K, V]) invalidateFills() {
+	for _, f := range c.fills {
+		f.stale = true
+	}
+}
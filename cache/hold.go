@@ -0,0 +1,44 @@
+package cache
+
+// hold keeps the backing store from refilling key for the WithRemoveHold
+// period after it was removed. The caller must hold the write lock.
+func (c *LRUCache[K, V]) hold(key K) {
+	if c.options.removeHold <= 0 {
+		return
+	}
+	if c.holds == nil {
+		c.holds = make(map[K]int64)
+	}
+	c.holds[key] = c.now().Add(c.options.removeHold).UnixNano()
+}
+
+// held reports whether key is within its WithRemoveHold period, forgetting
+// the hold once it has run out.
+func (c *LRUCache[K, V]) held(key K) bool {
+	if c.options.removeHold <= 0 {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	until, found := c.holds[key]
+	if !found {
+		return false
+	}
+	if c.now().UnixNano() < until {
+		return true
+	}
+	delete(c.holds, key)
+	return false
+}
+
+// releaseHolds forgets the holds that have run out, so that keys removed once
+// and never read again do not accumulate. The caller must hold the write lock.
+func (c *LRUCache[K, V]) releaseHolds() {
+	now := c.now().UnixNano()
+	for key, until := range c.holds {
+		if now >= until {
+			delete(c.holds, key)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vivekkothari/in-memory-cache/cache/testutil/clocktest"
+)
+
+// Test Case 1: A removed key is not refilled until its hold runs out
+func TestRemoveHold(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	cache := newTestCache(2, time.Minute, quietListener[string]{},
+		WithClock[string, string](clock),
+		WithRemoveHold[string, string](time.Second),
+	).(*LRUCache[string, string])
+	defer cache.Close()
+
+	cache.Put("keyX", "cached")
+	cache.Remove("keyX")
+	if value, found := cache.GetOk("keyX"); value != "" || found {
+		t.Errorf("Expected '' and false during the hold, got '%s' and %t", value, found)
+	}
+
+	clock.Advance(2 * time.Second)
+	if value := cache.Get("keyX"); value != "valueX" {
+		t.Errorf("Expected 'valueX' after the hold, got '%s'", value)
+	}
+	if value := len(cache.holds); value != 0 {
+		t.Errorf("Expected the hold to be forgotten, got '%d'", value)
+	}
+
+	// Storing the key ends the hold early.
+	cache.Remove("keyX")
+	cache.Put("keyX", "stored")
+	if _, held := cache.holds["keyX"]; held {
+		t.Errorf("Expected Put to end the hold on keyX")
+	}
+}
+
+// Test Case 2: Keys dropped by RemovePrefix are held like a Remove
+func TestRemovePrefixHolds(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	cache := NewLRUCache[string, string](10, time.Minute, func(key string) (string, bool) { return "stale", true },
+		quietListener[string]{}, time.Hour, WithClock[string, string](clock), WithRemoveHold[string, string](time.Second))
+	defer cache.Close()
+
+	cache.Put("user:1", "value")
+	RemovePrefix(cache, "user:")
+	if value, found := cache.GetOk("user:1"); found {
+		t.Errorf("Expected no refill during the hold, got '%s'", value)
+	}
+}
+
+// Test Case 3: Warmup skips held keys and drops a load whose key was removed
+func TestWarmupHolds(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(key string) (string, bool) {
+		if key == "keyB" {
+			close(started)
+			<-release
+		}
+		return "stale", true
+	}
+	cache := NewLRUCache[string, string](10, time.Minute, loader, quietListener[string]{}, time.Hour,
+		WithClock[string, string](clock), WithRemoveHold[string, string](time.Second))
+	defer cache.Close()
+
+	cache.Put("keyA", "value")
+	cache.Remove("keyA")
+	if loaded, err := cache.Warmup(context.Background(), []string{"keyA"}); loaded != 0 || err != nil {
+		t.Errorf("Expected 0 loaded and no error for a held key, got %d and %v", loaded, err)
+	}
+
+	done := make(chan int)
+	go func() {
+		loaded, _ := cache.Warmup(context.Background(), []string{"keyB"})
+		done <- loaded
+	}()
+	<-started
+	cache.Remove("keyB")
+	close(release)
+	if loaded := <-done; loaded != 0 {
+		t.Errorf("Expected 0 loaded for a key removed during its load, got %d", loaded)
+	}
+	if _, found := cache.cache["keyB"]; found {
+		t.Errorf("Expected keyB not to be stored")
+	}
+}
@@ -0,0 +1,99 @@
+package cache
+
+import "time"
+
+// Hooks are the functions Instrument calls around each operation of a cache.
+// Every field is optional. Before hooks receive the key before the operation
+// runs and After hooks receive it afterwards, with the time the operation
+// took; AfterGet also learns whether the value was found. Get and GetOk share
+// the Get hooks.
+type Hooks[K comparable] struct {
+	BeforePut    func(key K)
+	AfterPut     func(key K, elapsed time.Duration)
+	BeforeGet    func(key K)
+	AfterGet     func(key K, found bool, elapsed time.Duration)
+	BeforeRemove func(key K)
+	AfterRemove  func(key K, elapsed time.Duration)
+	BeforeClose  func()
+	AfterClose   func(elapsed time.Duration)
+}
+
+func (h Hooks[K]) empty() bool {
+	return h.BeforePut == nil && h.AfterPut == nil &&
+		h.BeforeGet == nil && h.AfterGet == nil &&
+		h.BeforeRemove == nil && h.AfterRemove == nil &&
+		h.BeforeClose == nil && h.AfterClose == nil
+}
+
+type instrumentedCache[K comparable, V any] struct {
+	inner Cache[K, V]
+	hooks Hooks[K]
+}
+
+// Instrument wraps inner so that hooks run around each of its operations, for
+// logging, metrics or tracing that should not depend on the implementation
+// underneath. With no hooks set it returns inner itself, and an operation
+// whose After hook is nil is not timed.
+func Instrument[K comparable, V any](inner Cache[K, V], hooks Hooks[K]) Cache[K, V] {
+	if hooks.empty() {
+		return inner
+	}
+	return &instrumentedCache[K, V]{inner: inner, hooks: hooks}
+}
+
+func (c *instrumentedCache[K, V]) Put(key K, value V, ttl ...time.Duration) {
+	if c.hooks.BeforePut != nil {
+		c.hooks.BeforePut(key)
+	}
+	if c.hooks.AfterPut == nil {
+		c.inner.Put(key, value, ttl...)
+		return
+	}
+	start := time.Now()
+	c.inner.Put(key, value, ttl...)
+	c.hooks.AfterPut(key, time.Since(start))
+}
+
+func (c *instrumentedCache[K, V]) Get(key K) V {
+	value, _ := c.GetOk(key)
+	return value
+}
+
+func (c *instrumentedCache[K, V]) GetOk(key K) (V, bool) {
+	if c.hooks.BeforeGet != nil {
+		c.hooks.BeforeGet(key)
+	}
+	if c.hooks.AfterGet == nil {
+		return c.inner.GetOk(key)
+	}
+	start := time.Now()
+	value, found := c.inner.GetOk(key)
+	c.hooks.AfterGet(key, found, time.Since(start))
+	return value, found
+}
+
+func (c *instrumentedCache[K, V]) Remove(key K) {
+	if c.hooks.BeforeRemove != nil {
+		c.hooks.BeforeRemove(key)
+	}
+	if c.hooks.AfterRemove == nil {
+		c.inner.Remove(key)
+		return
+	}
+	start := time.Now()
+	c.inner.Remove(key)
+	c.hooks.AfterRemove(key, time.Since(start))
+}
+
+func (c *instrumentedCache[K, V]) Close() {
+	if c.hooks.BeforeClose != nil {
+		c.hooks.BeforeClose()
+	}
+	if c.hooks.AfterClose == nil {
+		c.inner.Close()
+		return
+	}
+	start := time.Now()
+	c.inner.Close()
+	c.hooks.AfterClose(time.Since(start))
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// Test Case 1: Hooks see every operation, including Puts with a TTL
+func TestInstrument(t *testing.T) {
+	inner := newTestCache(2, 5*time.Second, quietListener[string]{})
+	var calls []string
+	cache := Instrument(inner, Hooks[string]{
+		BeforePut: func(key string) { calls = append(calls, "before put "+key) },
+		AfterGet: func(key string, found bool, elapsed time.Duration) {
+			if found {
+				calls = append(calls, "found "+key)
+			} else {
+				calls = append(calls, "missed "+key)
+			}
+		},
+		AfterRemove: func(key string, elapsed time.Duration) { calls = append(calls, "removed "+key) },
+		AfterClose:  func(elapsed time.Duration) { calls = append(calls, "closed") },
+	})
+
+	cache.Put("key1", "value1", time.Minute)
+	if ttl, _ := inner.(*LRUCache[string, string]).TTL("key1"); ttl <= 5*time.Second {
+		t.Errorf("Expected the TTL of a minute to pass through, got %v", ttl)
+	}
+	if value := cache.Get("key1"); value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+	cache.GetOk("key2")
+	cache.Remove("key1")
+	cache.Close()
+
+	expected := []string{"before put key1", "found key1", "missed key2", "removed key1", "closed"}
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("Expected '%s', got '%s'", expected[i], calls[i])
+		}
+	}
+}
+
+// Test Case 2: Without hooks the cache is returned unwrapped
+func TestInstrumentNoHooks(t *testing.T) {
+	inner := newTestCache(2, 5*time.Second, quietListener[string]{})
+	defer inner.Close()
+
+	if cache := Instrument(inner, Hooks[string]{}); cache != inner {
+		t.Errorf("Expected the inner cache itself, got %T", cache)
+	}
+}
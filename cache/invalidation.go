@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+)
+
+// InvalidationBus carries invalidations between caches that hold copies of
+// the same data, typically one per replica of a service. Publish sends key to
+// every other cache on the bus and Subscribe registers the function that
+// receives the keys they publish. A bus must not deliver a key back to the
+// cache that published it; a Redis or NATS implementation can tag messages
+// with the publishing node for that. LocalBus is an in-process bus for tests.
+type InvalidationBus[K comparable] interface {
+	Publish(key K) error
+	Subscribe(handler func(key K))
+}
+
+// publishInvalidation tells the other caches on the WithInvalidationBus bus
+// to drop key. put says whether key was written rather than removed, which
+// is only published under WithInvalidationOnPut.
+func (c *LRUCache[K, V]) publishInvalidation(put bool, keys ...K) {
+	if c.options.invalidationBus == nil || (put && !c.options.invalidateOnPut) {
+		return
+	}
+	for _, key := range keys {
+		if err := c.options.invalidationBus.Publish(key); err != nil {
+			c.onError(fmt.Errorf("cache: publishing the invalidation of %v: %w", key, err))
+		}
+	}
+}
+
+// invalidate drops key on behalf of another cache on the bus, reporting it
+// like a Remove but without publishing it again, which would echo it back.
+// Invalidations that arrive once the cache is closed are ignored.
+func (c *LRUCache[K, V]) invalidate(key K) {
+	if c.closed.Load() {
+		return
+	}
+	key = c.normalize(key)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.removeKey(key)
+}
+
+// LocalBus is a channel-based InvalidationBus for caches in one process,
+// mostly useful to test code built on WithInvalidationBus. Each cache joins
+// it through its own endpoint, returned by Join, and receives the keys
+// published on every other endpoint.
+type LocalBus[K comparable] struct {
+	buffer int
+	// mutex keeps Close from closing the endpoints' channels under a
+	// concurrent Publish.
+	mutex     sync.RWMutex
+	endpoints []*localEndpoint[K]
+	closed    bool
+	done      sync.WaitGroup
+}
+
+type localEndpoint[K comparable] struct {
+	bus      *LocalBus[K]
+	keys     chan K
+	mutex    sync.Mutex
+	handlers []func(K)
+}
+
+// NewLocalBus returns a LocalBus whose endpoints queue up to buffer keys each
+// before Publish waits for them.
+func NewLocalBus[K comparable](buffer int) *LocalBus[K] {
+	return &LocalBus[K]{buffer: buffer}
+}
+
+// Join adds an endpoint to the bus, to be passed to WithInvalidationBus. An
+// endpoint that joins a closed bus never receives anything.
+func (b *LocalBus[K]) Join() InvalidationBus[K] {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	e := &localEndpoint[K]{bus: b, keys: make(chan K, b.buffer)}
+	b.endpoints = append(b.endpoints, e)
+	if b.closed {
+		close(e.keys)
+	}
+	b.done.Add(1)
+	go func() {
+		defer b.done.Done()
+		for key := range e.keys {
+			e.mutex.Lock()
+			handlers := e.handlers
+			e.mutex.Unlock()
+			for _, handler := range handlers {
+				handler(key)
+			}
+		}
+	}()
+	return e
+}
+
+// Close delivers every key already published and shuts the bus down. Publish
+// fails with ErrClosed afterwards.
+func (b *LocalBus[K]) Close() {
+	b.mutex.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, e := range b.endpoints {
+			close(e.keys)
+		}
+	}
+	b.mutex.Unlock()
+	b.done.Wait()
+}
+
+func (e *localEndpoint[K]) Publish(key K) error {
+	e.bus.mutex.RLock()
+	defer e.bus.mutex.RUnlock()
+
+	if e.bus.closed {
+		return ErrClosed
+	}
+	for _, other := range e.bus.endpoints {
+		if other != e {
+			other.keys <- key
+		}
+	}
+	return nil
+}
+
+func (e *localEndpoint[K]) Subscribe(handler func(key K)) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.handlers = append(e.handlers, handler)
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func newBusCache(t *testing.T, bus InvalidationBus[string], listener CacheListener[string], opts ...Option[string, string]) *LRUCache[string, string] {
+	t.Helper()
+	opts = append([]Option[string, string]{
+		WithInvalidationBus[string, string](bus),
+		WithListener[string, string](listener),
+	}, opts...)
+	cache, err := New(opts...)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	return cache
+}
+
+// Test Case 1: A write drops the key from the other caches but not its own
+func TestInvalidationBusPut(t *testing.T) {
+	bus := NewLocalBus[string](16)
+	writerListener := NewCountingCacheListener[string]()
+	readerListener := NewCountingCacheListener[string]()
+	writer := newBusCache(t, bus.Join(), writerListener)
+	defer writer.Close()
+	reader := newBusCache(t, bus.Join(), readerListener, WithInvalidationOnPut[string, string](false))
+	defer reader.Close()
+
+	reader.Put("key", "stale") // Not published, so the writer is unaffected.
+	reader.Put("other", "value")
+	writer.Put("key", "fresh")
+	bus.Close() // Delivers everything published so far.
+
+	if reader.Contains("key") {
+		t.Errorf("Expected the reader's stale copy to be invalidated")
+	}
+	if !reader.Contains("other") {
+		t.Errorf("Expected the reader's other keys to stay")
+	}
+	if value := readerListener.removeMap["key"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := writer.Get("key"); value != "fresh" {
+		t.Errorf("Expected 'fresh', got '%s'", value)
+	}
+	if value := len(writerListener.removeMap); value != 0 {
+		t.Errorf("Expected the invalidation not to echo back, got %v", writerListener.removeMap)
+	}
+}
+
+// Test Case 2: Removals are published, and ignored by closed caches
+func TestInvalidationBusRemove(t *testing.T) {
+	bus := NewLocalBus[string](16)
+	closedListener := NewCountingCacheListener[string]()
+	first := newBusCache(t, bus.Join(), quietListener[string]{}, WithInvalidationOnPut[string, string](false))
+	defer first.Close()
+	second := newBusCache(t, bus.Join(), quietListener[string]{}, WithInvalidationOnPut[string, string](false))
+	defer second.Close()
+	closed := newBusCache(t, bus.Join(), closedListener, WithInvalidationOnPut[string, string](false))
+
+	for _, cache := range []*LRUCache[string, string]{first, second, closed} {
+		cache.Put("key1", "value")
+		cache.Put("key2", "value")
+	}
+	closed.Close()
+	first.Remove("key1")
+	second.RemoveAll([]string{"key2"})
+	bus.Close()
+
+	if first.Contains("key2") || second.Contains("key1") {
+		t.Errorf("Expected both removals to reach the other cache")
+	}
+	if !closed.Contains("key1") || len(closedListener.removeMap) != 0 {
+		t.Errorf("Expected the closed cache to ignore invalidations, got %v", closedListener.removeMap)
+	}
+	if err := bus.Join().Publish("key1"); err != ErrClosed {
+		t.Errorf("Expected '%v', got '%v'", ErrClosed, err)
+	}
+}
+
+// Test Case 3: ReplaceAll publishes the keys it drops and holds them
+func TestInvalidationBusReplaceAll(t *testing.T) {
+	bus := NewLocalBus[string](16)
+	writer := newBusCache(t, bus.Join(), quietListener[string]{},
+		WithInvalidationOnPut[string, string](false),
+		WithRemoveHold[string, string](time.Minute),
+	)
+	defer writer.Close()
+	reader := newBusCache(t, bus.Join(), quietListener[string]{}, WithInvalidationOnPut[string, string](false))
+	defer reader.Close()
+
+	for _, cache := range []*LRUCache[string, string]{writer, reader} {
+		cache.Put("old", "value")
+		cache.Put("kept", "value")
+	}
+	writer.ReplaceAll(map[string]string{"kept": "new"})
+	bus.Close()
+
+	if reader.Contains("old") || !reader.Contains("kept") {
+		t.Errorf("Expected only the dropped key to be invalidated elsewhere")
+	}
+	if !writer.held("old") {
+		t.Errorf("Expected the dropped key to be held like a Remove")
+	}
+}
+
+// Test Case 4: Compare-and-swap, GetOrSet and counter writes are published; failed swaps are not
+func TestInvalidationBusConditionalWrites(t *testing.T) {
+	bus := NewLocalBus[string](16)
+	writer := newBusCache(t, bus.Join(), quietListener[string]{})
+	defer writer.Close()
+	writer.Put("swapped", "old")
+	reader := newBusCache(t, bus.Join(), quietListener[string]{}, WithInvalidationOnPut[string, string](false))
+	defer reader.Close()
+
+	for _, key := range []string{"swapped", "unswapped", "set"} {
+		reader.Put(key, "stale")
+	}
+	if !CompareAndSwap(writer, "swapped", "old", "new") {
+		t.Errorf("Expected the swap to succeed")
+	}
+	if CompareAndSwap(writer, "unswapped", "old", "new") {
+		t.Errorf("Expected the swap of a missing key to fail")
+	}
+	writer.GetOrSet("set", "value")
+	bus.Close()
+
+	if reader.Contains("swapped") || reader.Contains("set") {
+		t.Errorf("Expected the swapped and set keys to be invalidated")
+	}
+	if !reader.Contains("unswapped") {
+		t.Errorf("Expected a failed swap not to be published")
+	}
+
+	counterBus := NewLocalBus[string](16)
+	counter := NewCounterCache[string, int](10, time.Minute, nil, quietListener[string]{}, time.Minute,
+		WithInvalidationBus[string, int](counterBus.Join()))
+	defer counter.Close()
+	peer := NewCounterCache[string, int](10, time.Minute, nil, quietListener[string]{}, time.Minute,
+		WithInvalidationBus[string, int](counterBus.Join()), WithInvalidationOnPut[string, int](false))
+	defer peer.Close()
+	peer.Put("hits", 5)
+	if value := counter.Increment("hits", 1); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	counterBus.Close()
+	if peer.Contains("hits") {
+		t.Errorf("Expected the incremented key to be invalidated")
+	}
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// checkInvariants verifies that the cache's internal structures agree with
+// each other: the policy holds exactly the entries of the map, each once and
+// under its own key, the weight, pin, tag and priority bookkeeping matches
+// the entries, and no key is both in memory and spilled. It takes the read
+// lock and is meant for tests and debugging.
+func (c *LRUCache[K, V]) checkInvariants() error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var err error
+	seen := make(map[K]bool, len(c.cache))
+	var weight int64
+	pinned := 0
+	priorities := make(map[int]int)
+	c.policy.entries(func(item *CacheItem[K, V]) bool {
+		if seen[item.key] {
+			err = fmt.Errorf("key %v appears twice in the policy", item.key)
+			return false
+		}
+		seen[item.key] = true
+		if c.cache[item.key] != item {
+			err = fmt.Errorf("policy entry for key %v is not the one in the map", item.key)
+			return false
+		}
+		// Stop rather than loop forever on a corrupted, cyclic structure.
+		if len(seen) > len(c.cache) {
+			err = fmt.Errorf("policy holds more entries than the map's %d", len(c.cache))
+			return false
+		}
+		weight += item.weight
+		if item.pinned {
+			pinned++
+		}
+		if item.priority != 0 {
+			priorities[item.priority]++
+		}
+		for _, tag := range item.tags {
+			if _, found := c.tags[tag][item.key]; !found {
+				err = fmt.Errorf("key %v is missing from the index of its tag %q", item.key, tag)
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(seen) != len(c.cache) {
+		return fmt.Errorf("policy holds %d entries, the map %d", len(seen), len(c.cache))
+	}
+	if weight != c.weight {
+		return fmt.Errorf("entries weigh %d, the running total is %d", weight, c.weight)
+	}
+	if pinned != c.pinned {
+		return fmt.Errorf("%d entries are pinned, the count is %d", pinned, c.pinned)
+	}
+	if !maps.Equal(priorities, c.priorities) {
+		return fmt.Errorf("entries have priorities %v, the counts are %v", priorities, c.priorities)
+	}
+	for tag, keys := range c.tags {
+		for key := range keys {
+			if item, found := c.cache[key]; !found || !slices.Contains(item.tags, tag) {
+				return fmt.Errorf("tag %q indexes key %v, which does not carry it", tag, key)
+			}
+		}
+	}
+	for key := range c.spilled {
+		if _, found := c.cache[key]; found {
+			return fmt.Errorf("key %v is both in memory and spilled", key)
+		}
+	}
+	return nil
+}
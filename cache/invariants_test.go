@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test Case 1: Internal structures stay consistent under mixed concurrent use
+func TestInvariantsUnderStress(t *testing.T) {
+	for _, policy := range []Policy{LRU, LFU, FIFO, SLRU, ARC, SecondChance, Random, SampledLRU} {
+		t.Run(fmt.Sprint(policy), func(t *testing.T) {
+			cache := NewLRUCache[string, string](50, 50*time.Millisecond, nil, quietListener[string]{}, 10*time.Millisecond,
+				WithPolicy[string, string](policy),
+				WithMaximumWeight[string, string](400, byLength))
+			defer cache.Close()
+
+			var wg sync.WaitGroup
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < 2000; i++ {
+						key := fmt.Sprint("key", (g*7+i)%80)
+						switch i % 10 {
+						case 0:
+							cache.PutTagged(key, "tagged", []string{fmt.Sprint("tag", g)})
+						case 1:
+							cache.Remove(key)
+						case 2:
+							cache.Pin(key)
+						case 3:
+							cache.Unpin(key)
+						case 4:
+							cache.InvalidateTag(fmt.Sprint("tag", (g+1)%8))
+						case 5:
+							cache.GetAndRemove(key)
+						case 6:
+							cache.Put(key, fmt.Sprint("value", i), time.Duration(i%3)*10*time.Millisecond+time.Millisecond)
+						default:
+							cache.Get(key)
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+
+			if err := cache.checkInvariants(); err != nil {
+				t.Error(err)
+			}
+			cache.Resize(20)
+			if err := cache.checkInvariants(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Test Case 2: The checker detects a map entry missing from the policy
+func TestInvariantsDetectCorruption(t *testing.T) {
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	if err := cache.checkInvariants(); err != nil {
+		t.Fatalf("Expected a consistent cache, got %v", err)
+	}
+
+	cache.policy.remove(cache.cache["key1"])
+	if err := cache.checkInvariants(); err == nil {
+		t.Errorf("Expected the checker to report the unlinked entry")
+	}
+}
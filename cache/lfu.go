@@ -0,0 +1,140 @@
+package cache
+
+import "time"
+
+// lfuBucket holds the items sharing one access frequency, most recently used
+// at the front.
+type lfuBucket[K comparable, V any] struct {
+	frequency uint64
+	items     itemList[K, V]
+	prev      *lfuBucket[K, V]
+	next      *lfuBucket[K, V]
+}
+
+// lfuPolicy is the O(1) LFU design: a list of frequency buckets in ascending
+// order, each holding a recency-ordered list of its items. Accessing an item
+// moves it to the bucket for the next frequency, creating it if needed, and
+// the victim is the least recent item of the lowest bucket.
+//
+// When decayInterval is set, every frequency is halved once per interval so
+// that formerly popular items eventually become evictable.
+type lfuPolicy[K comparable, V any] struct {
+	// head is a sentinel: head.next is the lowest frequency bucket.
+	head          lfuBucket[K, V]
+	decayInterval time.Duration
+	lastDecay     time.Time
+	clock         Clock
+}
+
+func newLFUPolicy[K comparable, V any](decayInterval time.Duration, clock Clock) *lfuPolicy[K, V] {
+	p := &lfuPolicy[K, V]{decayInterval: decayInterval, lastDecay: clock.Now(), clock: clock}
+	p.clear()
+	return p
+}
+
+func (p *lfuPolicy[K, V]) newBucketAfter(at *lfuBucket[K, V], frequency uint64) *lfuBucket[K, V] {
+	bucket := &lfuBucket[K, V]{frequency: frequency, prev: at, next: at.next}
+	bucket.items.init()
+	at.next.prev = bucket
+	at.next = bucket
+	return bucket
+}
+
+func (p *lfuPolicy[K, V]) unlinkIfEmpty(bucket *lfuBucket[K, V]) {
+	if bucket.items.len == 0 {
+		bucket.prev.next = bucket.next
+		bucket.next.prev = bucket.prev
+	}
+}
+
+func (p *lfuPolicy[K, V]) add(item *CacheItem[K, V]) {
+	p.maybeDecay()
+	bucket := p.head.next
+	if bucket == &p.head || bucket.frequency != 1 {
+		bucket = p.newBucketAfter(&p.head, 1)
+	}
+	bucket.items.pushFront(item)
+	item.bucket = bucket
+}
+
+func (p *lfuPolicy[K, V]) touch(item *CacheItem[K, V]) {
+	p.maybeDecay()
+	current := item.bucket
+	next := current.next
+	if next == &p.head || next.frequency != current.frequency+1 {
+		next = p.newBucketAfter(current, current.frequency+1)
+	}
+	current.items.remove(item)
+	next.items.pushFront(item)
+	item.bucket = next
+	p.unlinkIfEmpty(current)
+}
+
+func (p *lfuPolicy[K, V]) sharedHits() bool {
+	return false
+}
+
+func (p *lfuPolicy[K, V]) touchShared(*CacheItem[K, V]) {}
+
+func (p *lfuPolicy[K, V]) remove(item *CacheItem[K, V]) {
+	bucket := item.bucket
+	bucket.items.remove(item)
+	item.bucket = nil
+	p.unlinkIfEmpty(bucket)
+}
+
+func (p *lfuPolicy[K, V]) victims(yield func(*CacheItem[K, V]) bool) {
+	p.maybeDecay()
+	for bucket := p.head.next; bucket != &p.head; bucket = bucket.next {
+		for item := bucket.items.back(); item != nil; item = bucket.items.prev(item) {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+func (p *lfuPolicy[K, V]) entries(yield func(*CacheItem[K, V]) bool) {
+	for bucket := p.head.prev; bucket != &p.head; bucket = bucket.prev {
+		for item := bucket.items.front(); item != nil; item = bucket.items.next(item) {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+func (p *lfuPolicy[K, V]) clear() {
+	p.head.next = &p.head
+	p.head.prev = &p.head
+}
+
+func (p *lfuPolicy[K, V]) maybeDecay() {
+	now := p.clock.Now()
+	if p.decayInterval <= 0 || now.Sub(p.lastDecay) < p.decayInterval {
+		return
+	}
+	p.lastDecay = now
+
+	// Halving preserves the order of the buckets, so they are rebuilt from the
+	// highest frequency down. Buckets that collapse onto the same frequency are
+	// merged, with the items of the formerly more frequent bucket kept in front.
+	old := p.head.prev
+	p.clear()
+	var target *lfuBucket[K, V]
+	for bucket := old; bucket != &p.head; {
+		lower := bucket.prev
+		frequency := max(bucket.frequency/2, 1)
+		if target == nil || target.frequency != frequency {
+			target = p.newBucketAfter(&p.head, frequency)
+		}
+		for item := bucket.items.front(); item != nil; {
+			next := bucket.items.next(item)
+			bucket.items.remove(item)
+			target.items.pushBack(item)
+			item.bucket = target
+			item = next
+		}
+		bucket = lower
+	}
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newTestLFUCache(capacity int, defaultTTL time.Duration, listener CacheListener[string], opts ...Option[string, string]) *LRUCache[string, string] {
+	backingStore := func(key string) (string, bool) {
+		if key == "keyX" {
+			return "valueX", true
+		}
+		return "", false
+	}
+	return NewLFUCache[string, string](capacity, defaultTTL, backingStore, listener, 5*time.Second, opts...)
+}
+
+// Test Case 1: A popular key survives a scan of one-hit keys
+func TestLFUKeepsPopularKey(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestLFUCache(3, 5*time.Second, listener)
+
+	cache.Put("popular", "value")
+	for i := 0; i < 5; i++ {
+		cache.Get("popular")
+	}
+	for i := 0; i < 10; i++ {
+		cache.Put(fmt.Sprint("scan", i), "value")
+	}
+
+	if value := cache.Get("popular"); value != "value" {
+		t.Errorf("Expected 'value', got '%s'", value)
+	}
+	if value := listener.evictMap["popular"]; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+	if value := len(listener.evictMap); value != 8 {
+		t.Errorf("Expected '8', got '%d'", value)
+	}
+}
+
+// Test Case 2: Ties on frequency are broken by recency
+func TestLFUTieBreaksByRecency(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestLFUCache(3, 5*time.Second, listener)
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3")
+	cache.Get("key1")
+	cache.Get("key2")
+	cache.Put("key4", "value4") // Evicts key3, the only key used once
+
+	if value := listener.evictMap["key3"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	cache.Get("key4")
+	cache.Put("key5", "value5") // Evicts key1, the least recent of the keys used twice
+
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"key4", "key2", "key5"}) {
+		t.Errorf("Expected [key4 key2 key5], got %v", keys)
+	}
+}
+
+// Test Case 3: Decay lets a formerly popular key be evicted
+func TestLFUFrequencyDecay(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestLFUCache(2, 5*time.Second, listener, WithFrequencyDecay[string, string](50*time.Millisecond))
+
+	cache.Put("formerlyPopular", "value")
+	for i := 0; i < 3; i++ {
+		cache.Get("formerlyPopular")
+	}
+	cache.Put("recent", "value")
+
+	time.Sleep(60 * time.Millisecond)
+	cache.Get("recent")
+	time.Sleep(60 * time.Millisecond)
+	cache.Put("new", "value")
+
+	if value := listener.evictMap["formerlyPopular"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := listener.evictMap["recent"]; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 4: TTL, backing store and listener behave as they do for LRU
+func TestLFUExpiryAndBackingStore(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestLFUCache(2, 5*time.Second, listener)
+
+	cache.Put("key1", "value1", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond) // Wait for expiration
+	if value := cache.Get("key1"); value != "" {
+		t.Errorf("Expected '', got '%s'", value)
+	}
+	if value := listener.expireMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+
+	if value := cache.Get("keyX"); value != "valueX" {
+		t.Errorf("Expected 'valueX', got '%s'", value)
+	}
+	if value := listener.missMap["keyX"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	cache.Remove("keyX")
+	if value := cache.Len(); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
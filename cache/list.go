@@ -0,0 +1,81 @@
+package cache
+
+// itemList is an intrusive doubly linked list of cache items. Because the links
+// live in CacheItem itself, adding an entry costs no extra allocation and
+// walking the list needs no type assertions. The zero value is not usable;
+// call init first, and do not copy an itemList once initialised.
+type itemList[K comparable, V any] struct {
+	// root is a sentinel: root.next is the front and root.prev the back.
+	root CacheItem[K, V]
+	len  int
+}
+
+func (l *itemList[K, V]) init() {
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	l.len = 0
+}
+
+// front returns the first item, or nil if the list is empty.
+func (l *itemList[K, V]) front() *CacheItem[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// back returns the last item, or nil if the list is empty.
+func (l *itemList[K, V]) back() *CacheItem[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// next returns the item after item, or nil at the back of the list.
+func (l *itemList[K, V]) next(item *CacheItem[K, V]) *CacheItem[K, V] {
+	if item.next == &l.root {
+		return nil
+	}
+	return item.next
+}
+
+// prev returns the item before item, or nil at the front of the list.
+func (l *itemList[K, V]) prev(item *CacheItem[K, V]) *CacheItem[K, V] {
+	if item.prev == &l.root {
+		return nil
+	}
+	return item.prev
+}
+
+func (l *itemList[K, V]) insertAfter(item, at *CacheItem[K, V]) {
+	item.prev = at
+	item.next = at.next
+	at.next.prev = item
+	at.next = item
+	l.len++
+}
+
+func (l *itemList[K, V]) pushFront(item *CacheItem[K, V]) {
+	l.insertAfter(item, &l.root)
+}
+
+func (l *itemList[K, V]) pushBack(item *CacheItem[K, V]) {
+	l.insertAfter(item, l.root.prev)
+}
+
+func (l *itemList[K, V]) remove(item *CacheItem[K, V]) {
+	item.prev.next = item.next
+	item.next.prev = item.prev
+	item.prev = nil
+	item.next = nil
+	l.len--
+}
+
+func (l *itemList[K, V]) moveToFront(item *CacheItem[K, V]) {
+	if l.root.next == item {
+		return
+	}
+	l.remove(item)
+	l.pushFront(item)
+}
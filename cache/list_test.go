@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func listKeys(l *itemList[string, int]) []string {
+	var keys []string
+	for item := l.front(); item != nil; item = l.next(item) {
+		keys = append(keys, item.key)
+	}
+	return keys
+}
+
+func listKeysBackwards(l *itemList[string, int]) []string {
+	var keys []string
+	for item := l.back(); item != nil; item = l.prev(item) {
+		keys = append(keys, item.key)
+	}
+	return keys
+}
+
+// Test Case 1: Push, move and remove keep both directions consistent
+func TestItemListOperations(t *testing.T) {
+	var l itemList[string, int]
+	l.init()
+	if l.front() != nil || l.back() != nil {
+		t.Fatalf("Expected an empty list")
+	}
+
+	items := map[string]*CacheItem[string, int]{}
+	for _, key := range []string{"a", "b", "c"} {
+		items[key] = &CacheItem[string, int]{key: key}
+		l.pushFront(items[key])
+	}
+	d := &CacheItem[string, int]{key: "d"}
+	l.pushBack(d)
+
+	if keys := listKeys(&l); !slices.Equal(keys, []string{"c", "b", "a", "d"}) {
+		t.Errorf("Expected [c b a d], got %v", keys)
+	}
+
+	l.moveToFront(items["a"])
+	l.moveToFront(items["a"])
+	l.remove(items["b"])
+	if keys := listKeys(&l); !slices.Equal(keys, []string{"a", "c", "d"}) {
+		t.Errorf("Expected [a c d], got %v", keys)
+	}
+	if keys := listKeysBackwards(&l); !slices.Equal(keys, []string{"d", "c", "a"}) {
+		t.Errorf("Expected [d c a], got %v", keys)
+	}
+	if l.len != 3 {
+		t.Errorf("Expected '3', got '%d'", l.len)
+	}
+}
+
+// Test Case 2: Cache order follows puts, hits and updates
+func TestCacheRecencyOrder(t *testing.T) {
+	cache := NewLRUCache[string, int](3, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+
+	cache.Put("key1", 1)
+	cache.Put("key2", 2)
+	cache.Put("key3", 3)
+	cache.Get("key1")
+	cache.Put("key2", 20)
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"key2", "key1", "key3"}) {
+		t.Errorf("Expected [key2 key1 key3], got %v", keys)
+	}
+
+	cache.Put("key4", 4) // Evicts key3
+	cache.Remove("key1")
+	cache.Put("key5", 5)
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"key5", "key4", "key2"}) {
+		t.Errorf("Expected [key5 key4 key2], got %v", keys)
+	}
+}
+
+// Test Case 3: Eviction follows recency after heavy churn
+func TestCacheEvictionOrderAfterChurn(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, int](10, 5*time.Second, nil, listener, 5*time.Second)
+
+	for i := 0; i < 100; i++ {
+		cache.Put(fmt.Sprint("key", i), i)
+		if i%3 == 0 {
+			cache.Remove(fmt.Sprint("key", i))
+		}
+	}
+	expected := []string{"key98", "key97", "key95", "key94", "key92", "key91", "key89", "key88", "key86"}
+	if keys := cache.Keys(); !slices.Equal(keys, expected) {
+		t.Errorf("Expected %v, got %v", expected, keys)
+	}
+}
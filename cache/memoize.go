@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoizeOption configures Memoize and MemoizeContext.
+type MemoizeOption func(*memoizeOptions)
+
+type memoizeOptions struct {
+	errorTTL time.Duration
+}
+
+// WithErrorTTL makes a memoized function remember an error for ttl, returning
+// it again for the same argument instead of calling the function. By default
+// errors are not remembered, so the next call tries again.
+func WithErrorTTL(ttl time.Duration) MemoizeOption {
+	return func(o *memoizeOptions) {
+		o.errorTTL = ttl
+	}
+}
+
+// Memoize returns a function that serves fn's results from c, calling fn only
+// for arguments c does not hold and storing what it returns with c's default
+// TTL. Concurrent calls for the same argument share one call of fn.
+func Memoize[K comparable, V any](c Cache[K, V], fn func(K) (V, error), opts ...MemoizeOption) func(K) (V, error) {
+	memoized := MemoizeContext(c, func(_ context.Context, key K) (V, error) { return fn(key) }, opts...)
+	return func(key K) (V, error) {
+		return memoized(context.Background(), key)
+	}
+}
+
+// MemoizeContext is Memoize for a function that takes a context. The call of
+// fn that concurrent callers share gets the context of the caller that
+// started it; each caller stops waiting, with its context's error, once its
+// own context is done.
+func MemoizeContext[K comparable, V any](c Cache[K, V], fn func(context.Context, K) (V, error), opts ...MemoizeOption) func(context.Context, K) (V, error) {
+	m := &memoizer[K, V]{cache: c, fn: fn, calls: make(map[K]*memoizedCall[V])}
+	for _, opt := range opts {
+		opt(&m.options)
+	}
+	return m.call
+}
+
+type memoizer[K comparable, V any] struct {
+	cache   Cache[K, V]
+	fn      func(context.Context, K) (V, error)
+	options memoizeOptions
+	// mutex guards calls, the calls of fn in progress, and errs, the errors
+	// remembered under WithErrorTTL.
+	mutex sync.Mutex
+	calls map[K]*memoizedCall[V]
+	errs  map[K]memoizedError
+}
+
+type memoizedCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type memoizedError struct {
+	err   error
+	until time.Time
+}
+
+func (m *memoizer[K, V]) call(ctx context.Context, key K) (V, error) {
+	if value, found := m.cache.GetOk(key); found {
+		return value, nil
+	}
+
+	m.mutex.Lock()
+	if remembered, found := m.errs[key]; found {
+		if time.Now().Before(remembered.until) {
+			m.mutex.Unlock()
+			var zeroValue V
+			return zeroValue, remembered.err
+		}
+		delete(m.errs, key)
+	}
+	call, found := m.calls[key]
+	if !found {
+		call = &memoizedCall[V]{done: make(chan struct{})}
+		m.calls[key] = call
+		go m.run(ctx, key, call)
+	}
+	m.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zeroValue V
+		return zeroValue, ctx.Err()
+	}
+}
+
+// run calls fn for key and stores the outcome, so that callers arriving after
+// it finishes find it in the cache, or in errs, rather than calling fn again.
+func (m *memoizer[K, V]) run(ctx context.Context, key K, call *memoizedCall[V]) {
+	call.value, call.err = m.fn(ctx, key)
+	if call.err == nil {
+		m.cache.Put(key, call.value)
+	}
+
+	m.mutex.Lock()
+	delete(m.calls, key)
+	if call.err != nil && m.options.errorTTL > 0 {
+		now := time.Now()
+		if m.errs == nil {
+			m.errs = make(map[K]memoizedError)
+		}
+		for other, remembered := range m.errs {
+			if !now.Before(remembered.until) {
+				delete(m.errs, other)
+			}
+		}
+		m.errs[key] = memoizedError{err: call.err, until: now.Add(m.options.errorTTL)}
+	}
+	m.mutex.Unlock()
+	close(call.done)
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test Case 1: Concurrent calls for one argument run the function once
+func TestMemoizeSingleFlight(t *testing.T) {
+	cache := newTestCache(10, time.Minute, quietListener[string]{})
+	defer cache.Close()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	square := Memoize(cache, func(key string) (string, error) {
+		calls.Add(1)
+		<-release
+		return key + key, nil
+	})
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, err := square("ab"); value != "abab" || err != nil {
+				t.Errorf("Expected 'abab' and no error, got '%s' and '%v'", value, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond) // Let the callers pile up on the call.
+	close(release)
+	wg.Wait()
+
+	if value := calls.Load(); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Get("ab"); value != "abab" {
+		t.Errorf("Expected 'abab' to be cached, got '%s'", value)
+	}
+}
+
+// Test Case 2: Errors are retried unless WithErrorTTL remembers them
+func TestMemoizeErrors(t *testing.T) {
+	errBoom := errors.New("boom")
+	for _, tc := range []struct {
+		name     string
+		opts     []MemoizeOption
+		expected int32
+	}{
+		{"not remembered", nil, 2},
+		{"remembered", []MemoizeOption{WithErrorTTL(time.Minute)}, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cache := newTestCache(10, time.Minute, quietListener[string]{})
+			defer cache.Close()
+			var calls atomic.Int32
+			fail := Memoize(cache, func(key string) (string, error) {
+				calls.Add(1)
+				return "", errBoom
+			}, tc.opts...)
+
+			for range 2 {
+				if _, err := fail("key"); !errors.Is(err, errBoom) {
+					t.Errorf("Expected '%v', got '%v'", errBoom, err)
+				}
+			}
+			if value := calls.Load(); value != tc.expected {
+				t.Errorf("Expected '%d', got '%d'", tc.expected, value)
+			}
+		})
+	}
+}
+
+// Test Case 3: A caller whose context ends stops waiting for the shared call
+func TestMemoizeContextCancel(t *testing.T) {
+	cache := newTestCache(10, time.Minute, quietListener[string]{})
+	defer cache.Close()
+	release := make(chan struct{})
+	slow := MemoizeContext(cache, func(ctx context.Context, key string) (string, error) {
+		<-release
+		return "value", nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := slow(ctx, "key"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected '%v', got '%v'", context.Canceled, err)
+	}
+	close(release)
+	if value, err := slow(context.Background(), "key"); value != "value" || err != nil {
+		t.Errorf("Expected 'value' and no error, got '%s' and '%v'", value, err)
+	}
+}
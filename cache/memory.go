@@ -0,0 +1,53 @@
+package cache
+
+import "unsafe"
+
+// Sizer can be implemented by keys and values whose memory footprint the
+// estimator used by WithMaxMemory cannot see, such as structs holding
+// pointers, slices or maps. SizeBytes should return the bytes held beyond
+// the value's own fixed size.
+type Sizer interface {
+	SizeBytes() int64
+}
+
+// estimateEntrySize approximates the bytes held by one entry: the CacheItem,
+// which embeds the list links and the fixed-size parts of the key and value,
+// the map slot pointing at it, and whatever strings, byte slices and Sizers
+// reference beyond that. It is an estimate: allocator rounding, map growth
+// and memory behind other pointers are not counted.
+func estimateEntrySize[K comparable, V any](key K, value V) int64 {
+	var item CacheItem[K, V]
+	mapSlot := unsafe.Sizeof(key) + unsafe.Sizeof(&item) + 1
+	return int64(unsafe.Sizeof(item)+mapSlot) + referencedSize(key) + referencedSize(value)
+}
+
+// referencedSize returns the bytes v references outside its fixed size.
+func referencedSize(v any) int64 {
+	switch v := v.(type) {
+	case Sizer:
+		return v.SizeBytes()
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(cap(v))
+	default:
+		return 0
+	}
+}
+
+// EstimatedMemory returns an estimate of the bytes held by the entries. With
+// WithMaxMemory it is the running total that eviction works against;
+// otherwise it is computed from every entry on each call.
+func (c *LRUCache[K, V]) EstimatedMemory() int64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.options.memoryBound {
+		return c.weight
+	}
+	var total int64
+	for key, item := range c.cache {
+		total += estimateEntrySize(key, item.value)
+	}
+	return total
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sizedValue struct {
+	payload []int64
+}
+
+func (v sizedValue) SizeBytes() int64 {
+	return int64(cap(v.payload)) * 8
+}
+
+// Test Case 1: The estimate counts strings, byte slices and Sizers
+func TestEstimateEntrySize(t *testing.T) {
+	base := estimateEntrySize("", "")
+	if value := estimateEntrySize("key", strings.Repeat("a", 100)) - base; value != 103 {
+		t.Errorf("Expected '103', got '%d'", value)
+	}
+	if value := estimateEntrySize[string, any]("", make([]byte, 10, 64)) - estimateEntrySize[string, any]("", nil); value != 64 {
+		t.Errorf("Expected '64', got '%d'", value)
+	}
+	if value := estimateEntrySize[string, any]("", sizedValue{make([]int64, 16)}) - estimateEntrySize[string, any]("", nil); value != 128 {
+		t.Errorf("Expected '128', got '%d'", value)
+	}
+}
+
+// Test Case 2: A memory-bound cache stays near its bound under churn
+func TestMaxMemoryUnderChurn(t *testing.T) {
+	const limit = 1 << 20
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	cache := NewLRUCache[string, string](1<<30, time.Minute, nil, quietListener[string]{}, time.Minute,
+		WithMaxMemory[string, string](limit))
+	defer cache.Close()
+	for i := 0; i < 20000; i++ {
+		cache.Put(fmt.Sprint("key", i), strings.Repeat("v", 1024))
+	}
+
+	if value := cache.EstimatedMemory(); value > limit {
+		t.Errorf("Expected at most '%d', got '%d'", limit, value)
+	}
+	if value := cache.Len(); value < 500 {
+		t.Errorf("Expected roughly 900 entries, got '%d'", value)
+	}
+
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	if grown := int64(after.HeapAlloc) - int64(before.HeapAlloc); grown > 3*limit {
+		t.Errorf("Expected the heap to grow by at most '%d', got '%d'", 3*limit, grown)
+	}
+	runtime.KeepAlive(cache)
+}
+
+// Test Case 3: EstimatedMemory works without a memory bound
+func TestEstimatedMemoryUnbounded(t *testing.T) {
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	if value, expected := cache.EstimatedMemory(), 2*estimateEntrySize("key1", "value1"); value != expected {
+		t.Errorf("Expected '%d', got '%d'", expected, value)
+	}
+}
@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NamespacedKey is the key a NamespacedCache stores an entry of a namespace
+// under, and the key its listener is given.
+type NamespacedKey[K comparable] struct {
+	Namespace string
+	Key       K
+}
+
+// NamespacedCache holds several kinds of entry, each in its own namespace, in
+// one cache. The namespaces share the capacity and the LRU order, so a busy
+// namespace can take room from a quiet one, but each keeps its own counters
+// and can be cleared on its own. The embedded LRUCache gives access to the
+// cache as a whole.
+type NamespacedCache[K comparable, V any] struct {
+	*LRUCache[NamespacedKey[K], V]
+	mutex sync.Mutex
+	stats map[string]*namespaceCounters
+}
+
+type namespaceCounters struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+}
+
+// NewNamespaced is New for a NamespacedCache. The loader and the listener, if
+// any, see the namespace of every key.
+func NewNamespaced[K comparable, V any](opts ...Option[NamespacedKey[K], V]) (*NamespacedCache[K, V], error) {
+	c := &NamespacedCache[K, V]{stats: make(map[string]*namespaceCounters)}
+	listener := &namespaceListener[K, V]{cache: c, next: collectOptions(opts).listener}
+	opts = append(opts[:len(opts):len(opts)], WithListener[NamespacedKey[K], V](listener))
+	cache, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.LRUCache = cache
+	return c, nil
+}
+
+// Namespace returns the view of the cache holding the entries of namespace
+// name. Closing the view does nothing; close the NamespacedCache instead.
+func (c *NamespacedCache[K, V]) Namespace(name string) Cache[K, V] {
+	return &namespaceView[K, V]{cache: c, name: name}
+}
+
+// NamespaceStats returns the counters of namespace name and the number of
+// entries it holds. Capacity and the weights describe the cache as a whole.
+func (c *NamespacedCache[K, V]) NamespaceStats(name string) CacheStats {
+	stats := c.Stats()
+	counters := c.counters(name)
+	size := 0
+	for _, key := range c.Keys() {
+		if key.Namespace == name {
+			size++
+		}
+	}
+	return CacheStats{
+		Hits:        counters.hits.Load(),
+		Misses:      counters.misses.Load(),
+		Evictions:   counters.evictions.Load(),
+		Expirations: counters.expirations.Load(),
+		Size:        size,
+		Capacity:    stats.Capacity,
+		Weight:      stats.Weight,
+		MaxWeight:   stats.MaxWeight,
+	}
+}
+
+// ClearNamespace removes every entry of namespace name, reporting each like a
+// Remove, and returns how many there were.
+func (c *NamespacedCache[K, V]) ClearNamespace(name string) int {
+	var keys []NamespacedKey[K]
+	for _, key := range c.Keys() {
+		if key.Namespace == name {
+			keys = append(keys, key)
+		}
+	}
+	return c.RemoveAll(keys)
+}
+
+func (c *NamespacedCache[K, V]) counters(name string) *namespaceCounters {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	counters, found := c.stats[name]
+	if !found {
+		counters = new(namespaceCounters)
+		c.stats[name] = counters
+	}
+	return counters
+}
+
+type namespaceView[K comparable, V any] struct {
+	cache *NamespacedCache[K, V]
+	name  string
+}
+
+func (v *namespaceView[K, V]) key(key K) NamespacedKey[K] {
+	return NamespacedKey[K]{Namespace: v.name, Key: key}
+}
+
+func (v *namespaceView[K, V]) Put(key K, value V, ttl ...time.Duration) {
+	v.cache.Put(v.key(key), value, ttl...)
+}
+
+func (v *namespaceView[K, V]) Get(key K) V {
+	return v.cache.Get(v.key(key))
+}
+
+func (v *namespaceView[K, V]) GetOk(key K) (V, bool) {
+	return v.cache.GetOk(v.key(key))
+}
+
+func (v *namespaceView[K, V]) Remove(key K) {
+	v.cache.Remove(v.key(key))
+}
+
+func (v *namespaceView[K, V]) Close() {}
+
+// namespaceListener keeps the per-namespace counters and passes every event on
+// to the listener the cache was configured with. It implements LoadListener so
+// that a miss is counted once however it ends; the next listener is told of a
+// load as a miss unless it is a LoadListener itself. It implements
+// ContextListener too, passing the context on to a next listener that wants it.
+type namespaceListener[K comparable, V any] struct {
+	cache *NamespacedCache[K, V]
+	next  CacheListener[NamespacedKey[K]]
+}
+
+func (l *namespaceListener[K, V]) OnHit(key NamespacedKey[K]) {
+	l.OnHitContext(context.Background(), key)
+}
+
+func (l *namespaceListener[K, V]) OnHitContext(ctx context.Context, key NamespacedKey[K]) {
+	l.cache.counters(key.Namespace).hits.Add(1)
+	if listener, ok := l.next.(ContextListener[NamespacedKey[K]]); ok {
+		listener.OnHitContext(ctx, key)
+	} else if l.next != nil {
+		l.next.OnHit(key)
+	}
+}
+
+func (l *namespaceListener[K, V]) OnMiss(key NamespacedKey[K]) {
+	l.OnMissContext(context.Background(), key)
+}
+
+func (l *namespaceListener[K, V]) OnMissContext(ctx context.Context, key NamespacedKey[K]) {
+	l.cache.counters(key.Namespace).misses.Add(1)
+	l.missed(ctx, key)
+}
+
+func (l *namespaceListener[K, V]) OnLoad(key NamespacedKey[K]) {
+	l.OnLoadContext(context.Background(), key)
+}
+
+func (l *namespaceListener[K, V]) OnLoadContext(ctx context.Context, key NamespacedKey[K]) {
+	l.cache.counters(key.Namespace).misses.Add(1)
+	listener, ok := l.next.(LoadListener[NamespacedKey[K]])
+	if !ok {
+		l.missed(ctx, key)
+		return
+	}
+	if contextual, ok := l.next.(ContextListener[NamespacedKey[K]]); ok {
+		contextual.OnLoadContext(ctx, key)
+	} else {
+		listener.OnLoad(key)
+	}
+}
+
+// missed tells the next listener of a miss.
+func (l *namespaceListener[K, V]) missed(ctx context.Context, key NamespacedKey[K]) {
+	if listener, ok := l.next.(ContextListener[NamespacedKey[K]]); ok {
+		listener.OnMissContext(ctx, key)
+	} else if l.next != nil {
+		l.next.OnMiss(key)
+	}
+}
+
+func (l *namespaceListener[K, V]) OnEvict(key NamespacedKey[K]) {
+	l.cache.counters(key.Namespace).evictions.Add(1)
+	if l.next != nil {
+		l.next.OnEvict(key)
+	}
+}
+
+func (l *namespaceListener[K, V]) OnExpire(key NamespacedKey[K]) {
+	l.cache.counters(key.Namespace).expirations.Add(1)
+	if l.next != nil {
+		l.next.OnExpire(key)
+	}
+}
+
+func (l *namespaceListener[K, V]) OnRemove(key NamespacedKey[K]) {
+	if listener, ok := l.next.(RemovalListener[NamespacedKey[K]]); ok {
+		listener.OnRemove(key)
+	}
+}
+
+func (l *namespaceListener[K, V]) OnRefresh(key NamespacedKey[K]) {
+	if listener, ok := l.next.(RefreshListener[NamespacedKey[K]]); ok {
+		listener.OnRefresh(key)
+	}
+}
+
+func (l *namespaceListener[K, V]) OnReject(key NamespacedKey[K]) {
+	if listener, ok := l.next.(RejectListener[NamespacedKey[K]]); ok {
+		listener.OnReject(key)
+	}
+}
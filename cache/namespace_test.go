@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"testing"
+)
+
+func newNamespacedCache(t *testing.T, opts ...Option[NamespacedKey[string], string]) *NamespacedCache[string, string] {
+	t.Helper()
+	cache, err := NewNamespaced(opts...)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	t.Cleanup(cache.Close)
+	return cache
+}
+
+// Test Case 1: Namespaces keep their keys apart and count their own events
+func TestNamespaceIsolation(t *testing.T) {
+	listener := NewCountingCacheListener[NamespacedKey[string]]()
+	cache := newNamespacedCache(t, WithListener[NamespacedKey[string], string](listener))
+	users := cache.Namespace("users")
+	orders := cache.Namespace("orders")
+
+	users.Put("42", "alice")
+	orders.Put("42", "order-42")
+
+	if value := users.Get("42"); value != "alice" {
+		t.Errorf("Expected 'alice', got '%s'", value)
+	}
+	if value := orders.Get("42"); value != "order-42" {
+		t.Errorf("Expected 'order-42', got '%s'", value)
+	}
+	if _, found := orders.GetOk("7"); found {
+		t.Errorf("Expected key 7 to be missing from orders")
+	}
+
+	if stats := cache.NamespaceStats("users"); stats.Hits != 1 || stats.Misses != 0 || stats.Size != 1 {
+		t.Errorf("Expected 1 hit, 0 misses and size 1, got %+v", stats)
+	}
+	if stats := cache.NamespaceStats("orders"); stats.Hits != 1 || stats.Misses != 1 || stats.Size != 1 {
+		t.Errorf("Expected 1 hit, 1 miss and size 1, got %+v", stats)
+	}
+	if value := listener.missMap[NamespacedKey[string]{Namespace: "orders", Key: "7"}]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 2: Namespaces share one capacity and LRU order
+func TestNamespaceSharedCapacity(t *testing.T) {
+	cache := newNamespacedCache(t,
+		WithCapacity[NamespacedKey[string], string](2),
+		WithListener[NamespacedKey[string], string](quietListener[NamespacedKey[string]]{}),
+	)
+	users := cache.Namespace("users")
+	sessions := cache.Namespace("sessions")
+
+	users.Put("1", "alice")
+	sessions.Put("a", "session-a")
+	sessions.Put("b", "session-b")
+
+	if _, found := users.GetOk("1"); found {
+		t.Errorf("Expected the oldest entry, in users, to be evicted")
+	}
+	if stats := cache.NamespaceStats("users"); stats.Evictions != 1 || stats.Capacity != 2 {
+		t.Errorf("Expected 1 eviction and capacity 2, got %+v", stats)
+	}
+	if stats := cache.NamespaceStats("sessions"); stats.Evictions != 0 || stats.Size != 2 {
+		t.Errorf("Expected 0 evictions and size 2, got %+v", stats)
+	}
+}
+
+// Test Case 3: ClearNamespace leaves the other namespaces alone
+func TestClearNamespace(t *testing.T) {
+	listener := NewCountingCacheListener[NamespacedKey[string]]()
+	cache := newNamespacedCache(t, WithListener[NamespacedKey[string], string](listener))
+	users := cache.Namespace("users")
+	orders := cache.Namespace("orders")
+
+	users.Put("1", "alice")
+	users.Put("2", "bob")
+	orders.Put("1", "order-1")
+
+	if removed := cache.ClearNamespace("users"); removed != 2 {
+		t.Errorf("Expected '2', got '%d'", removed)
+	}
+	if value := cache.Len(); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := orders.Get("1"); value != "order-1" {
+		t.Errorf("Expected 'order-1', got '%s'", value)
+	}
+	if value := listener.removeMap[NamespacedKey[string]{Namespace: "users", Key: "2"}]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
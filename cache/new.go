@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// New returns a cache configured entirely by options, validated as
+// NewLRUCacheChecked validates its arguments. Without WithDefaultTTL entries
+// never expire unless they are stored with a TTL, without WithCapacity the
+// cache is unbounded, without WithLoader misses are not loaded, without
+// WithListener events are discarded and without WithCleanupInterval expired
+// entries are swept every minute.
+func New[K comparable, V any](opts ...Option[K, V]) (*LRUCache[K, V], error) {
+	o := collectOptions(opts)
+	if o.shards != 0 {
+		return nil, errors.New("cache: WithShards needs NewSharded")
+	}
+	return NewLRUCacheChecked(o.capacity, o.defaultTTL, o.loader, o.eventListener(), o.cleanupInterval, opts...)
+}
+
+// NewSharded is New for a ShardedLRUCache, with the shard count set by
+// WithShards, one by default.
+func NewSharded[K comparable, V any](opts ...Option[K, V]) (*ShardedLRUCache[K, V], error) {
+	o := collectOptions(opts)
+	shards := o.shards
+	if shards == 0 {
+		shards = 1
+	}
+	return NewShardedLRUCacheChecked(shards, o.capacity, o.defaultTTL, o.loader, o.eventListener(), o.cleanupInterval, opts...)
+}
+
+// eventListener returns the WithListener listener, or one that discards
+// events, rather than leaving the positional constructors to fall back to
+// the printing NoOpCacheListener.
+func (o options[K, V]) eventListener() CacheListener[K] {
+	if o.listener == nil {
+		return quietListener[K]{}
+	}
+	return o.listener
+}
+
+func collectOptions[K comparable, V any](opts []Option[K, V]) options[K, V] {
+	o := defaultOptions[K, V]()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithCapacity sets the maximum number of entries; zero, the default, leaves
+// the cache unbounded.
+func WithCapacity[K comparable, V any](capacity int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.capacity = capacity
+	}
+}
+
+// WithDefaultTTL sets the TTL of entries stored without one. The default, zero,
+// means that such entries never expire.
+func WithDefaultTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.defaultTTL = ttl
+	}
+}
+
+// WithLoader sets the backing store that misses are loaded from.
+func WithLoader[K comparable, V any](loader func(key K) (V, bool)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.loader = loader
+	}
+}
+
+// WithLoaderE sets a backing store that can fail, in place of WithLoader or
+// the positional backing store. It returns ErrNotFound, possibly wrapped, for
+// a key that does not exist; any other error is a failed load, which GetE
+// reports as a *LoadError and which, unlike a missing key, is never
+// remembered by WithNegativeCache.
+func WithLoaderE[K comparable, V any](loader func(key K) (V, error)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.loaderE = loader
+	}
+}
+
+// WithLoaderContext is WithLoaderE for a loader that itself reads from the
+// cache. The loader is given a context recording the chain of loads in
+// progress; reading the cache with GetContext and that context lets the
+// cache tell a dependency on another key, which is loaded as usual, from a
+// cycle, which fails with ErrLoadCycle.
+func WithLoaderContext[K comparable, V any](loader func(ctx context.Context, key K) (V, error)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.loaderContext = loader
+	}
+}
+
+// WithLoaderTTL is WithLoaderE for a loader that also decides how long each
+// value it returns is cached, for example from an HTTP Cache-Control header. A
+// zero TTL leaves the choice to the rules of WithFillTTL.
+func WithLoaderTTL[K comparable, V any](loader func(key K) (V, time.Duration, error)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.loaderTTL = loader
+	}
+}
+
+// WithLoaderFor routes the keys match accepts to loader, for a cache whose
+// keys come from different places, such as "user:" keys from one service and
+// "order:" keys from another. It can be given several times: the routes are
+// tried in the order they were added and the first match loads the key, while
+// keys no route matches go to the loader set by the other options, or are not
+// found if there is none. Routing applies to every load, including those of
+// Refresh, WithRefreshAhead and Warmup, but not to a GetWithLoader loader,
+// which takes precedence. The route a loaded key took is reported in the
+// Loader field of its EventLoad.
+func WithLoaderFor[K comparable, V any](match func(key K) bool, loader func(key K) (V, bool)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.loaderRoutes = append(o.loaderRoutes, loaderRoute[K, V]{match: match, loader: loader})
+	}
+}
+
+// loaderRoute is one WithLoaderFor route.
+type loaderRoute[K comparable, V any] struct {
+	match  func(K) bool
+	loader func(K) (V, bool)
+}
+
+// WithFillTTL sets the TTL of values loaded from the backing store. The TTL of
+// a loaded value is, in order of precedence, the one a WithLoaderTTL loader
+// returned with it, the TTL of the expired entry it refreshes, the fill TTL
+// and finally the default TTL.
+func WithFillTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.fillTTL = ttl
+	}
+}
+
+// WithListener sets the listener that receives the cache's events.
+func WithListener[K comparable, V any](listener CacheListener[K]) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.listener = listener
+	}
+}
+
+// WithCleanupInterval sets how often expired entries are swept; the default
+// is a minute.
+func WithCleanupInterval[K comparable, V any](interval time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.cleanupInterval = interval
+	}
+}
+
+// WithShards sets the number of shards of a cache built by NewSharded.
+func WithShards[K comparable, V any](shards int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.shards = shards
+	}
+}
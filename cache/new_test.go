@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Case 1: New builds a cache from options alone
+func TestNew(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache, err := New(
+		WithCapacity[string, string](2),
+		WithDefaultTTL[string, string](5*time.Second),
+		WithLoader(func(key string) (string, bool) { return "loaded " + key, true }),
+		WithListener[string, string](listener),
+		WithCleanupInterval[string, string](time.Second),
+		WithPolicy[string, string](FIFO),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3")
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Get("key4"); value != "loaded key4" {
+		t.Errorf("Expected 'loaded key4', got '%s'", value)
+	}
+	if value := cache.cleanupInterval; value != time.Second {
+		t.Errorf("Expected '1s', got '%v'", value)
+	}
+}
+
+// Test Case 2: Nonsensical combinations are reported as errors
+func TestNewErrors(t *testing.T) {
+	_, err := New(WithCapacity[string, string](-1), WithShards[string, string](4))
+	if err == nil || !strings.Contains(err.Error(), "WithShards") {
+		t.Errorf("Expected the error to mention 'WithShards', got '%v'", err)
+	}
+
+	_, err = New(WithCapacity[string, string](-1), WithDefaultTTL[string, string](time.Second))
+	if err == nil || !strings.Contains(err.Error(), "capacity") {
+		t.Errorf("Expected a capacity error, got '%v'", err)
+	}
+
+	_, err = New(WithRefreshAhead[string, string](time.Second, 1, 1))
+	if err == nil || !strings.Contains(err.Error(), "WithRefreshAhead") {
+		t.Errorf("Expected refresh-ahead without a loader to be rejected, got '%v'", err)
+	}
+}
+
+// Test Case 3: NewSharded takes the shard count as an option
+func TestNewSharded(t *testing.T) {
+	cache, err := NewSharded(
+		WithShards[int, int](4),
+		WithCapacity[int, int](100),
+		WithDefaultTTL[int, int](time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+	if value := len(cache.shards); value != 4 {
+		t.Errorf("Expected '4', got '%d'", value)
+	}
+	if value := cache.Capacity(); value != 100 {
+		t.Errorf("Expected '100', got '%d'", value)
+	}
+}
+
+// Test Case 4: Without WithListener, New prints nothing
+func TestNewDiscardsEvents(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	cache, err := New(WithCapacity[string, string](1))
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	sharded, err := NewSharded(WithCapacity[string, string](2), WithShards[string, string](2))
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	for _, c := range []Cache[string, string]{cache, sharded} {
+		c.Put("key1", "value1")
+		c.Put("key2", "value2")
+		c.Get("key2")
+		c.Get("missing")
+		c.Close()
+	}
+
+	w.Close()
+	os.Stdout = stdout
+	printed, _ := io.ReadAll(r)
+	if len(printed) != 0 {
+		t.Errorf("Expected nothing on stdout, got %q", printed)
+	}
+}
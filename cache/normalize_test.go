@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Case 1: Keys that normalize alike share an entry
+func TestKeyNormalizer(t *testing.T) {
+	var loaded []string
+	backingStore := func(key string) (string, bool) {
+		loaded = append(loaded, key)
+		return "loaded", true
+	}
+	cache := NewLRUCache[string, string](5, 5*time.Second, backingStore, quietListener[string]{}, 5*time.Second,
+		WithKeyNormalizer[string, string](strings.ToLower))
+
+	cache.Put("ABC", "value")
+	if value := cache.Get("abc"); value != "value" {
+		t.Errorf("Expected 'value', got '%s'", value)
+	}
+	if !cache.Contains("aBc") {
+		t.Errorf("Expected 'aBc' to be contained")
+	}
+	if keys := cache.Keys(); len(keys) != 1 || keys[0] != "abc" {
+		t.Errorf("Expected [abc], got %v", keys)
+	}
+
+	cache.Remove("Abc")
+	if cache.Contains("abc") {
+		t.Errorf("Expected 'abc' to be removed")
+	}
+
+	cache.Get("XYZ")
+	if len(loaded) != 1 || loaded[0] != "xyz" {
+		t.Errorf("Expected [xyz], got %v", loaded)
+	}
+}
+
+// Test Case 2: Sharded caches route normalized keys to one shard
+func TestShardedKeyNormalizer(t *testing.T) {
+	cache := NewShardedLRUCache[string, string](8, 64, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithKeyNormalizer[string, string](strings.ToLower))
+	defer cache.Close()
+
+	cache.Put("MixedCase", "value")
+	if value := cache.Get("mixedcase"); value != "value" {
+		t.Errorf("Expected 'value', got '%s'", value)
+	}
+	if cache.ShardIndex("MIXEDCASE") != cache.ShardIndex("mixedcase") {
+		t.Errorf("Expected both spellings on the same shard")
+	}
+}
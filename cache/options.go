@@ -0,0 +1,612 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Option configures an LRUCache. Options are the arguments of New and
+// NewSharded, and trailing arguments to the older positional constructors.
+type Option[K comparable, V any] func(*options[K, V])
+
+type options[K comparable, V any] struct {
+	// The settings New and NewSharded take instead of positional arguments.
+	capacity        int
+	defaultTTL      time.Duration
+	loader          func(K) (V, bool)
+	loaderE         func(K) (V, error)
+	loaderContext   func(context.Context, K) (V, error)
+	loaderTTL       func(K) (V, time.Duration, error)
+	loaderRoutes    []loaderRoute[K, V]
+	fillTTL         time.Duration
+	listener        CacheListener[K]
+	cleanupInterval time.Duration
+	shards          int
+
+	cleanupStrategy CleanupStrategy
+	sampleSize      int
+	maxSampleRounds int
+	canEvict        func(K, V) bool
+	hasher          func(K) uint64
+
+	snapshotInterval time.Duration
+	snapshotWriter   func() (io.Writer, error)
+	persistPath      string
+	snapshotPath     string
+	errorHandler     func(error)
+	codec            Codec[K, V]
+
+	heapLimit    uint64
+	shedFraction float64
+	heapInUse    func() uint64
+
+	initialEntries []Entry[K, V]
+
+	clock Clock
+
+	maxConcurrentLoads int
+	prefetchLoader     func(K) (V, map[K]V, bool)
+
+	negativeCapacity int
+	negativeTTL      time.Duration
+
+	promoteOneIn int
+	loadTimeout  time.Duration
+
+	policy          Policy
+	frequencyDecay  time.Duration
+	probationRatio  float64
+	allowStaleRead  bool
+	admission       Admission
+	evictionPolicy  func() EvictionPolicy[K]
+	evictionSamples int
+	score           func(ScoredEntry[K]) float64
+	maxWeight       int64
+	weigher         func(K, V) int64
+	memoryBound     bool
+	drainExpired    chan<- ExpiredEntry[K, V]
+	drainPolicy     DrainPolicy
+	pinnedNoExpiry  bool
+	autoResize      *AutoResize
+	normalize       func(K) K
+
+	compressThreshold int
+	cloneValue        func(V) V
+	cloneOnPut        bool
+
+	evictionRateWindow time.Duration
+
+	asyncListener   bool
+	listenerWorkers int
+	listenerBuffer  int
+
+	refreshWindow  time.Duration
+	refreshWorkers int
+	refreshQueue   int
+
+	lockStripes int
+
+	invalidationBus InvalidationBus[K]
+	invalidateOnPut bool
+
+	removeHold time.Duration
+
+	spill SpillStore[K, V]
+
+	lowWatermark          float64
+	watermarkInBackground bool
+
+	cacheBackingResults bool
+}
+
+func defaultOptions[K comparable, V any]() options[K, V] {
+	return options[K, V]{
+		cleanupInterval: time.Minute,
+		cleanupStrategy: FullScanCleanup,
+		probationRatio:  0.2,
+		evictionSamples: 5,
+		codec:           GobCodec[K, V]{},
+		heapInUse:       heapInUse,
+		clock:           realClock{},
+
+		evictionRateWindow: time.Minute,
+
+		cacheBackingResults: true,
+		invalidateOnPut:     true,
+	}
+}
+
+// WithSampledCleanup switches the background cleanup to SampledCleanup. Every
+// tick samples sampleSize entries, expires the stale ones and repeats, up to
+// maxRounds rounds, while more than a quarter of the sample was expired.
+func WithSampledCleanup[K comparable, V any](sampleSize, maxRounds int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.cleanupStrategy = SampledCleanup
+		o.sampleSize = sampleSize
+		o.maxSampleRounds = maxRounds
+	}
+}
+
+// WithCanEvict installs a callback that can veto the eviction of an entry, for
+// example one representing a resource that is still in use. Vetoed entries are
+// kept and the next-oldest entry is considered instead; if every entry is
+// vetoed the cache grows beyond its capacity.
+func WithCanEvict[K comparable, V any](canEvict func(key K, value V) bool) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.canEvict = canEvict
+	}
+}
+
+// WithHasher overrides the function a ShardedLRUCache uses to assign keys to
+// shards; a key lands in shard hasher(key) % shards. This is the place to plug
+// in a well-distributed hash of the relevant fields of a struct key. It is
+// ignored by a plain LRUCache.
+func WithHasher[K comparable, V any](hasher func(key K) uint64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.hasher = hasher
+	}
+}
+
+// WithPrefetchLoader replaces the backing store with loader, which returns,
+// besides the value for the requested key, entries for related keys that the
+// data source produced along with it, such as the rest of a page of rows. The
+// related entries are cached with the WithFillTTL TTL, unless their keys
+// already hold a live entry, so that later reads of them hit without calling
+// the loader again.
+func WithPrefetchLoader[K comparable, V any](loader func(key K) (V, map[K]V, bool)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.prefetchLoader = loader
+	}
+}
+
+// WithPersistence makes NewLRUCache load the snapshot at path, written with
+// SaveTo, and Close save one there, so that entries survive a restart. A
+// missing file starts the cache empty, and a file that cannot be read or
+// written leaves the cache cold rather than failing; the error goes to the
+// WithErrorHandler handler. It has no effect on a ShardedLRUCache.
+func WithPersistence[K comparable, V any](path string) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.persistPath = path
+	}
+}
+
+// WithPeriodicSnapshot makes the cleanup goroutine write a snapshot of the
+// cache, encoded with the WithCodec codec, every interval, and Close write a
+// final one. newWriter is called for each snapshot; if the writer it returns
+// is also an io.Closer it is closed once the snapshot has been written.
+// Snapshots that fail are skipped and reported to the WithErrorHandler
+// handler.
+func WithPeriodicSnapshot[K comparable, V any](interval time.Duration, newWriter func() (io.Writer, error)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.snapshotInterval = interval
+		o.snapshotWriter = newWriter
+	}
+}
+
+// WithNegativeCache remembers up to capacity keys that the backing store did
+// not find, for ttl, so that repeated lookups of absent keys do not reach the
+// store. Negative entries live in their own small LRU and never take capacity
+// away from real values. GetOrLoadNeg remembers the misses of the loaders it
+// is given in a second LRU of the same size. A Put of the key forgets its
+// negative entries.
+func WithNegativeCache[K comparable, V any](capacity int, ttl time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.negativeCapacity = capacity
+		o.negativeTTL = ttl
+	}
+}
+
+// WithApproximateLRU promotes an entry to the front of the order list on only
+// one in promoteOneIn hits, chosen at random. Hits that are not promoted are
+// served under the read lock, which greatly improves read throughput under
+// contention at the cost of slightly less accurate eviction: a hot entry is
+// still very likely to have been promoted recently.
+//
+// Because such hits run concurrently, the listener's OnHit may be called from
+// several goroutines at once and must be safe for concurrent use.
+func WithApproximateLRU[K comparable, V any](promoteOneIn int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.promoteOneIn = promoteOneIn
+	}
+}
+
+// WithLoadTimeout bounds how long Get waits for the backing store, as measured
+// by the cache's Clock. A load that takes longer fails: GetE returns a
+// *LoadError wrapping context.DeadlineExceeded, Get returns the zero value,
+// and the timeout is counted in Stats. Unlike a key that was not found, the
+// key is not remembered by WithNegativeCache. The abandoned load is left to
+// finish in the background and its result is discarded, not cached.
+func WithLoadTimeout[K comparable, V any](timeout time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.loadTimeout = timeout
+	}
+}
+
+// WithPolicy selects the eviction policy. The default is LRU.
+func WithPolicy[K comparable, V any](policy Policy) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.policy = policy
+	}
+}
+
+// WithFrequencyDecay halves every access frequency once per interval under the
+// LFU policy, so that keys which were popular once but no longer are can
+// eventually be evicted.
+func WithFrequencyDecay[K comparable, V any](interval time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.frequencyDecay = interval
+	}
+}
+
+// WithProbationRatio sets the share of the capacity reserved for the probation
+// segment under the SLRU policy. The default is 0.2.
+func WithProbationRatio[K comparable, V any](ratio float64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.probationRatio = ratio
+	}
+}
+
+// WithAllowStaleRead makes Get return an expired entry's value instead of
+// reloading it from the backing store. The entry stays in place until the
+// cleanup goroutine expires it; unlike stale-while-revalidate, no refresh is
+// triggered.
+func WithAllowStaleRead[K comparable, V any]() Option[K, V] {
+	return func(o *options[K, V]) {
+		o.allowStaleRead = true
+	}
+}
+
+// WithAdmission sets the admission filter consulted when a full cache would
+// evict to make room for a new key. With TinyLFU, rejected keys are counted in
+// Stats and reported to a listener that implements RejectListener. Hits are
+// recorded under the write lock so the frequency sketch stays consistent,
+// which disables read-locked hits for FIFO and approximate LRU.
+func WithAdmission[K comparable, V any](admission Admission) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.admission = admission
+	}
+}
+
+// WithCacheBackingResults controls whether values loaded from the backing store
+// are stored in the cache. It defaults to true; pass false when the backing
+// store is itself a fast cache, so that only values given to Put are held.
+func WithCacheBackingResults[K comparable, V any](cacheBackingResults bool) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.cacheBackingResults = cacheBackingResults
+	}
+}
+
+// WithEvictionPolicy replaces the built-in eviction policy with one returned by
+// newPolicy, which is called once per cache, or once per shard of a
+// ShardedLRUCache. NewLRUPolicy, NewFIFOPolicy and NewRandomPolicy can be
+// passed directly. It takes precedence over WithPolicy.
+func WithEvictionPolicy[K comparable, V any](newPolicy func() EvictionPolicy[K]) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.evictionPolicy = newPolicy
+	}
+}
+
+// WithEvictionSamples sets how many entries the SampledLRU and Scored policies
+// sample to pick each victim. The default is 5; larger samples track LRU, or
+// the score, more closely at a higher eviction cost.
+func WithEvictionSamples[K comparable, V any](samples int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.evictionSamples = samples
+	}
+}
+
+// WithScoring selects the Scored policy, which evicts the entry score values
+// least, so that eviction can weigh recency, frequency, size and anything the
+// key implies against each other. To stay cheap it scores a sample of
+// WithEvictionSamples entries on each eviction rather than every entry;
+// raise the sample size for a choice closer to exact. Hits are counted under
+// the read lock.
+func WithScoring[K comparable, V any](score func(entry ScoredEntry[K]) float64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.policy = Scored
+		o.score = score
+	}
+}
+
+// WithMaximumWeight bounds the total weight of the entries, as computed by
+// weigher, in addition to the entry capacity. An entry is weighed when it is
+// inserted and again whenever its value changes, and least recently used
+// entries are evicted until the total fits. An entry heavier than maxWeight on
+// its own is not stored; it is counted as a rejection and reported to a
+// RejectListener. A ShardedLRUCache splits maxWeight evenly across its shards.
+func WithMaximumWeight[K comparable, V any](maxWeight int64, weigher func(key K, value V) int64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.maxWeight = maxWeight
+		o.weigher = weigher
+		o.memoryBound = false
+	}
+}
+
+// WithMaxMemory bounds the cache to roughly bytes of memory by weighing each
+// entry with a built-in size estimate: fixed-size keys and values count their
+// size, strings and byte slices their length, and types implementing Sizer
+// report their own size, on top of a per-entry bookkeeping overhead. The
+// bound is only as accurate as the estimate; memory behind pointers is
+// invisible unless a Sizer reports it. EstimatedMemory returns the running
+// total. It replaces any weigher set by WithMaximumWeight.
+func WithMaxMemory[K comparable, V any](bytes int64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.maxWeight = bytes
+		o.weigher = estimateEntrySize[K, V]
+		o.memoryBound = true
+	}
+}
+
+// WithDrainExpired delivers every expired entry, with its value, to ch as it
+// is reaped, whether by the cleanup goroutine or by a lookup that finds it
+// stale, so that a consumer can release resources the values hold. Entries
+// that are evicted or removed are not delivered. policy decides what happens
+// when ch is full. The send happens while the cache's lock is held, so under
+// DrainBlock the consumer must keep up and must not call back into the cache,
+// and Close blocks until a pending send completes. The cache never closes ch;
+// a ShardedLRUCache delivers the entries of every shard to the same ch.
+func WithDrainExpired[K comparable, V any](ch chan<- ExpiredEntry[K, V], policy DrainPolicy) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.drainExpired = ch
+		o.drainPolicy = policy
+	}
+}
+
+// WithPinnedNoExpiry exempts pinned entries from their TTL as well as from
+// eviction, until they are unpinned.
+func WithPinnedNoExpiry[K comparable, V any]() Option[K, V] {
+	return func(o *options[K, V]) {
+		o.pinnedNoExpiry = true
+	}
+}
+
+// WithAutoResize runs a controller on the cleanup goroutine that resizes the
+// cache between config.MinCapacity and config.MaxCapacity, growing it while
+// the hit ratio is low and shrinking it while the ratio is close to 1. The
+// initial capacity is clamped into those bounds. Capacity and ResizeDecisions
+// show what the controller did. It has no effect on a ShardedLRUCache, whose
+// shards have no cleanup goroutine of their own.
+func WithAutoResize[K comparable, V any](config AutoResize) Option[K, V] {
+	return func(o *options[K, V]) {
+		config.setDefaults()
+		o.autoResize = &config
+	}
+}
+
+// WithKeyNormalizer canonicalizes every key passed to the cache, for example
+// by lowercasing it, before it is looked up or stored, so that keys which
+// normalize alike share an entry. The backing store is called with the
+// normalized key, and Keys returns normalized keys.
+func WithKeyNormalizer[K comparable, V any](normalize func(key K) K) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.normalize = normalize
+	}
+}
+
+// WithAsyncListener runs listener callbacks on workers goroutines instead of
+// inside the cache operation, usually with the lock held, that caused them, so
+// that a slow listener does not stall the cache. Each worker queues up to
+// buffer callbacks; when a worker's queue is full the operation waits for
+// room, so a listener must not call back into the cache or it can deadlock.
+//
+// Every event for a key goes to the same worker, so a key's events are
+// delivered in the order they happened, but events for different keys can be
+// delivered in any order relative to each other. Close delivers every queued
+// event before it returns; events raised after Close run synchronously.
+func WithAsyncListener[K comparable, V any](workers, buffer int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.asyncListener = true
+		o.listenerWorkers = workers
+		o.listenerBuffer = buffer
+	}
+}
+
+// WithRefreshAhead reloads entries from the backing store before they expire:
+// a hit on an entry with less than window of its TTL left queues a reload in
+// the background and is served the current value meanwhile. The reload keeps
+// the entry's TTL. Reloads run on a fixed pool of workers goroutines fed by a
+// queue of up to queue keys; when the queue is full the reload is skipped,
+// and counted in Stats, rather than holding up the read. A sharded cache has
+// a pool per shard. New rejects it for a cache with nothing to reload from.
+func WithRefreshAhead[K comparable, V any](window time.Duration, workers, queue int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.refreshWindow = window
+		o.refreshWorkers = workers
+		o.refreshQueue = queue
+	}
+}
+
+// WithLockStripes makes concurrent misses of the same key share one
+// backing-store load: the first caller loads the key while the others wait
+// for it and are served the value it loaded. Misses of different keys never
+// wait for each other, and no lock is held while the loader runs, so a
+// loader may read other keys of the cache. A loader that reads its own key
+// waits for itself; GetContext with WithLoaderContext reports such a cycle
+// as ErrLoadCycle instead. stripes also sets the number of locks behind
+// LockKey; 256 is a good size.
+func WithLockStripes[K comparable, V any](stripes int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.lockStripes = stripes
+	}
+}
+
+// WithInvalidationBus connects the cache to bus, so that caches holding copies
+// of the same data drop a key everywhere when it changes in one of them. The
+// cache publishes every key it writes or removes, through Put, Set, Remove
+// and their variants, compare-and-swap and counter updates, and removes the
+// keys published by the other caches, reporting them to the listener like a
+// Remove without publishing them again. Values loaded from the backing store
+// are not published. Publish errors go to the WithErrorHandler handler.
+func WithInvalidationBus[K comparable, V any](bus InvalidationBus[K]) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.invalidationBus = bus
+	}
+}
+
+// WithInvalidationOnPut sets whether writes are published on the
+// WithInvalidationBus bus as well as removals; the default is true. Turn it
+// off when the other caches are expected to load the new value themselves
+// only once their copy expires.
+func WithInvalidationOnPut[K comparable, V any](enabled bool) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.invalidateOnPut = enabled
+	}
+}
+
+// WithRemoveHold keeps a removed key from being refilled from the backing
+// store for hold after Remove, RemoveAll, GetAndRemove or an invalidation from
+// the WithInvalidationBus bus, so that a read racing with the removal cannot
+// load the stale value back from a backing store that has not caught up yet.
+// Reads of the key during the hold are misses that return nothing. Storing
+// the key ends its hold.
+func WithRemoveHold[K comparable, V any](hold time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.removeHold = hold
+	}
+}
+
+// WithSpill moves evicted entries to store instead of discarding them, and
+// takes them back into memory, with the TTL they had, when a read misses them
+// before it would go to the backing store. Only evictions spill: expired,
+// removed and overwritten entries are deleted from store, and an entry that
+// expires while spilled is dropped when next read or by the cleanup
+// goroutine. Tags survive a spill but priorities do not. store is called
+// while the cache's lock is held, so its speed bounds that of evictions; the
+// cache does not close it, and entries spilled by an earlier process are not
+// read back.
+func WithSpill[K comparable, V any](store SpillStore[K, V]) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.spill = store
+	}
+}
+
+// WithEvictionRateWindow sets the window EvictionRate averages over, in whole
+// seconds; the default is a minute.
+func WithEvictionRateWindow[K comparable, V any](window time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.evictionRateWindow = window
+	}
+}
+
+// WithWatermarks amortizes eviction: when a Put finds the cache full, it
+// evicts down to low, a fraction of the capacity such as 0.9, in one batch
+// instead of evicting a single entry, firing OnEvict for each victim. With
+// inBackground the batch runs on the cleanup goroutine, serialized with the
+// expiry sweep, and the triggering Put returns without evicting; the cache may
+// then exceed its capacity by up to one batch until the goroutine catches up,
+// beyond which Put evicts inline again. Caches owned by a ShardedLRUCache
+// always evict in the foreground.
+func WithWatermarks[K comparable, V any](low float64, inBackground bool) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.lowWatermark = low
+		o.watermarkInBackground = inBackground
+	}
+}
+
+// WithCompression stores []byte values longer than threshold bytes gzipped,
+// when that makes them smaller, and decompresses them on every read, trading
+// CPU on Put and Get for memory. Reads return exactly the bytes that were
+// stored. The weigher and WithMaxMemory see the compressed size, so a weight
+// or memory bound holds more compressed entries. Values of any other type are
+// stored as is. An entry whose stored data fails to decompress is dropped and
+// treated as a miss.
+func WithCompression[K comparable, V any](threshold int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.compressThreshold = threshold
+	}
+}
+
+// WithValueCloner makes reads hand out clone(value) rather than the stored
+// value, so that callers mutating what Get returns cannot change the cached
+// copy seen by everyone else. With onPut, writes also store a clone, so that
+// callers cannot mutate a value after storing it either. CloneBytes and
+// CloneMethod cover common value types. Every read then pays for a copy,
+// which for large or deeply nested values can cost far more than the lookup
+// itself; without this option values are shared by reference.
+func WithValueCloner[K comparable, V any](clone func(V) V, onPut bool) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.cloneValue = clone
+		o.cloneOnPut = onPut
+	}
+}
+
+// WithSnapshotInterval makes the cleanup goroutine save the cache to path every
+// interval, in the SaveTo format, and Close save a final snapshot. Each
+// snapshot copies the entries under the read lock, so it is a consistent
+// point-in-time view, and is written to a temporary file that is renamed over
+// path, so a crash midway leaves the previous snapshot intact. Combine it with
+// WithPersistence on the same path to load the snapshot at startup. Failures
+// go to the WithErrorHandler handler; the duration and start time of the last
+// successful snapshot appear in Stats.
+func WithSnapshotInterval[K comparable, V any](interval time.Duration, path string) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.snapshotInterval = interval
+		o.snapshotPath = path
+	}
+}
+
+// WithErrorHandler sets a function that receives the errors of work the cache
+// does in the background or on Close, such as writing snapshots, which would
+// otherwise be dropped. It is called from the goroutine doing that work.
+func WithErrorHandler[K comparable, V any](handler func(error)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.errorHandler = handler
+	}
+}
+
+// WithCodec sets the encoding of the snapshots written by SaveTo,
+// WithPersistence, WithSnapshotInterval and WithPeriodicSnapshot, and read by
+// LoadFrom. The default is GobCodec; JSONCodec produces files that other tools
+// and people can read and edit. Files must be loaded with the codec that wrote
+// them.
+func WithCodec[K comparable, V any](codec Codec[K, V]) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.codec = codec
+	}
+}
+
+// WithMaxConcurrentLoads limits the backing-store calls in flight at once, from
+// misses and from Warmup together, to n, protecting the store from a burst of
+// misses. Further loads wait for a slot.
+func WithMaxConcurrentLoads[K comparable, V any](n int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.maxConcurrentLoads = n
+	}
+}
+
+// WithMemoryPressure makes the cleanup goroutine check the size of the Go heap
+// on every tick and, while it exceeds heapLimit bytes, evict shedFraction of
+// the entries, least recently used first, firing OnEvict for each. The heap
+// includes garbage not yet collected, so the check can trigger shortly after
+// other parts of the program free memory. A sharded cache sheds that share
+// of every shard. NewSoftCache configures this with half the entries shed per
+// tick.
+func WithMemoryPressure[K comparable, V any](heapLimit uint64, shedFraction float64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.heapLimit = heapLimit
+		o.shedFraction = shedFraction
+	}
+}
+
+// WithInitialEntries makes the constructor store entries before the cache is
+// returned, in slice order, so that the last entry is the most recently used.
+// An entry with a TTL of DefaultTTL gets the default TTL, and one with a zero
+// TTL never expires. Entries beyond the capacity evict earlier ones under the
+// usual rules; those evictions are counted in Stats but not reported to the
+// listener.
+func WithInitialEntries[K comparable, V any](entries []Entry[K, V]) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.initialEntries = entries
+	}
+}
+
+// WithClock makes the cache read time from clock instead of the system clock,
+// for Put timestamps, expiry checks and the cleanup goroutine's ticks alike.
+// It exists for tests, which can use clocktest.FakeClock to expire entries and
+// run cleanup deterministically.
+func WithClock[K comparable, V any](clock Clock) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.clock = clock
+	}
+}
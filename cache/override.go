@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// loaderOverride is the loader GetWithLoader supplies for one read of key. It
+// travels down the miss path in the read's context, along with the negative
+// cache its misses go to, if any.
+type loaderOverride[K comparable, V any] struct {
+	cache    *LRUCache[K, V]
+	key      K
+	loader   func(K) (V, bool)
+	ttl      time.Duration
+	negative *LRUCache[K, struct{}]
+}
+
+type loaderOverrideKey struct{}
+
+// context returns the context of a read that loads with o.
+func (o *loaderOverride[K, V]) context() context.Context {
+	return context.WithValue(context.Background(), loaderOverrideKey{}, o)
+}
+
+// GetWithLoader is Get for a key the configured loader cannot load, such as
+// one of several kinds of key that each come from their own service. On a
+// miss the value is loaded with loader instead and cached with ttl, if given,
+// or else the TTL a loaded value would get. Hits, expiry and listener events
+// are those of Get; loader is never called for a live entry. A key loader
+// does not find is not remembered by WithNegativeCache, and a key remembered
+// there is still offered to loader.
+//
+// Under WithLockStripes, concurrent misses of key share one load whatever
+// loader each supplied: the first to start loading wins, and the others are
+// served its value without calling their own loader.
+func (c *LRUCache[K, V]) GetWithLoader(key K, loader func(K) (V, bool), ttl ...time.Duration) V {
+	key = c.normalize(key)
+	override := &loaderOverride[K, V]{cache: c, key: key, loader: loader}
+	if len(ttl) > 0 {
+		override.ttl = ttl[0]
+	}
+	value, _ := c.lookup(override.context(), key)
+	return value
+}
+
+// GetOrLoadNeg is GetWithLoader for a key that many callers may miss at once.
+// Concurrent calls for key share one read, so a stampede calls loader at most
+// once, and report whether the value was found. Callers that wait for
+// another's read fire no listener events of their own.
+//
+// Misses are remembered only under WithNegativeCache; without it, every call
+// after the shared read misses anew. With it, a key loader did not find is
+// remembered as absent for the negative TTL, and calls until then return
+// false without calling loader. These misses are kept apart from those of the
+// configured loaders: they answer only GetOrLoadNeg, whichever loader it is
+// given, and never hide the key from Get or GetWithLoader.
+func (c *LRUCache[K, V]) GetOrLoadNeg(key K, loader func(K) (V, bool)) (V, bool) {
+	key = c.normalize(key)
+	override := &loaderOverride[K, V]{cache: c, key: key, loader: loader, negative: c.callNegative}
+	value, err, _ := c.share(key, readSlot[V], func() (V, error) {
+		return c.lookup(override.context(), key)
+	})
+	return value, err == nil
+}
+
+// readSlot picks the GetOrLoadNeg read of a fill for share.
+func readSlot[V any](f *fill[V]) **sharedLoad[V] {
+	return &f.read
+}
+
+// overriddenLoader returns the loader GetWithLoader or GetOrLoadNeg supplied
+// for the load of key by c that ctx belongs to, if any.
+func overriddenLoader[K comparable, V any](ctx context.Context, c *LRUCache[K, V], key K) (*loaderOverride[K, V], bool) {
+	override, ok := ctx.Value(loaderOverrideKey{}).(*loaderOverride[K, V])
+	if !ok || override.cache != c || override.key != key {
+		return nil, false
+	}
+	return override, true
+}
+
+// route returns the position, from 1, of the first WithLoaderFor route that
+// matches key, or zero if none does.
+func (c *LRUCache[K, V]) route(key K) int {
+	for i, route := range c.options.loaderRoutes {
+		if route.match(key) {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,255 @@
+package cache
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vivekkothari/in-memory-cache/cache/testutil/clocktest"
+)
+
+// Test Case 1: The supplied loader serves the miss; Get keeps the default one
+func TestGetWithLoader(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	listener := NewCountingCacheListener[string]()
+	cache := newTestCache(10, 10*time.Second, listener, WithClock[string, string](clock)).(*LRUCache[string, string])
+	defer cache.Close()
+
+	orders := func(key string) (string, bool) {
+		return "order-" + strings.TrimPrefix(key, "order:"), strings.HasPrefix(key, "order:")
+	}
+	if value := cache.GetWithLoader("order:1", orders, time.Minute); value != "order-1" {
+		t.Errorf("Expected 'order-1', got '%s'", value)
+	}
+	if ttl, _ := cache.TTL("order:1"); ttl != time.Minute {
+		t.Errorf("Expected '%v', got '%v'", time.Minute, ttl)
+	}
+	if value := cache.GetWithLoader("order:1", func(string) (string, bool) {
+		t.Errorf("Expected the loader not to be called for a live entry")
+		return "", false
+	}); value != "order-1" {
+		t.Errorf("Expected 'order-1', got '%s'", value)
+	}
+	if value := cache.GetWithLoader("user:1", orders); value != "" {
+		t.Errorf("Expected '', got '%s'", value)
+	}
+	if value := cache.Get("keyX"); value != "valueX" {
+		t.Errorf("Expected 'valueX', got '%s'", value)
+	}
+	if listener.hitMap["order:1"] != 1 || listener.missMap["order:1"] != 1 || listener.missMap["user:1"] != 1 {
+		t.Errorf("Expected the events of Get, got hits %v, misses %v", listener.hitMap, listener.missMap)
+	}
+
+	clock.Advance(2 * time.Minute)
+	if value := cache.GetWithLoader("order:1", orders); value != "order-1" || listener.expireMap["order:1"] != 1 {
+		t.Errorf("Expected the expired entry to be reloaded, got '%s'", value)
+	}
+}
+
+// Test Case 2: Concurrent misses with different loaders share the first load
+func TestGetWithLoaderSharesLoad(t *testing.T) {
+	loader := &blockingLoader{started: make(chan string, 2), release: make(chan struct{})}
+	var calls atomic.Int32
+	cache, err := New(
+		WithLockStripes[string, string](16),
+		WithListener[string, string](quietListener[string]{}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	first := make(chan string)
+	go func() {
+		first <- cache.GetWithLoader("key", func(key string) (string, bool) {
+			calls.Add(1)
+			loader.loads.Add(1)
+			return loader.load(key)
+		})
+	}()
+	<-loader.started
+
+	second := make(chan string)
+	go func() {
+		second <- cache.GetWithLoader("key", func(string) (string, bool) {
+			calls.Add(1)
+			return "second", true
+		})
+	}()
+	time.Sleep(10 * time.Millisecond) // Let the second read reach the stripe.
+	close(loader.release)
+
+	if value := <-first; value != "refreshed-key" {
+		t.Errorf("Expected 'refreshed-key', got '%s'", value)
+	}
+	if value := <-second; value != "refreshed-key" {
+		t.Errorf("Expected 'refreshed-key', got '%s'", value)
+	}
+	if value := calls.Load(); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 3: Keys go to the first matching route, the rest to the default
+func TestLoaderFor(t *testing.T) {
+	prefix := func(p string) func(string) bool {
+		return func(key string) bool { return strings.HasPrefix(key, p) }
+	}
+	source := func(name string) func(string) (string, bool) {
+		return func(key string) (string, bool) { return name + ":" + key, true }
+	}
+	cache, err := New(
+		WithListener[string, string](quietListener[string]{}),
+		WithLoaderFor(prefix("user:"), source("users")),
+		WithLoaderFor(prefix("u"), source("shadowed")),
+		WithLoaderFor(prefix("order:"), source("orders")),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+	events, _ := cache.Subscribe(EventLoad)
+
+	if value := cache.Get("user:1"); value != "users:user:1" {
+		t.Errorf("Expected 'users:user:1', got '%s'", value)
+	}
+	if value := cache.Get("order:1"); value != "orders:order:1" {
+		t.Errorf("Expected 'orders:order:1', got '%s'", value)
+	}
+	if _, found := cache.GetOk("item:1"); found {
+		t.Errorf("Expected a key no route matches to be missing without a default loader")
+	}
+	if loaded, _ := cache.Warmup(context.Background(), []string{"order:2"}); loaded != 1 || cache.Get("order:2") != "orders:order:2" {
+		t.Errorf("Expected Warmup to use the route, got %d loaded", loaded)
+	}
+	if value, _ := cache.Refresh("user:1", false); value != "users:user:1" {
+		t.Errorf("Expected 'users:user:1', got '%s'", value)
+	}
+
+	for _, expected := range []int{1, 3} {
+		if event := <-events; event.Loader != expected {
+			t.Errorf("Expected '%d', got '%d' for %s", expected, event.Loader, event.Key)
+		}
+	}
+	if value := cache.GetWithLoader("user:2", source("override")); value != "override:user:2" {
+		t.Errorf("Expected 'override:user:2', got '%s'", value)
+	}
+	if event := <-events; event.Loader != 0 {
+		t.Errorf("Expected '0', got '%d' for %s", event.Loader, event.Key)
+	}
+
+	var matches atomic.Int32
+	fallback, err := New(
+		WithListener[string, string](quietListener[string]{}),
+		WithLoader[string, string](source("default")),
+		WithLoaderFor(func(key string) bool {
+			matches.Add(1)
+			return strings.HasPrefix(key, "user:")
+		}, source("users")),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer fallback.Close()
+	fallbackEvents, _ := fallback.Subscribe(EventLoad)
+	if value := fallback.Get("item:1"); value != "default:item:1" {
+		t.Errorf("Expected 'default:item:1', got '%s'", value)
+	}
+	if event := <-fallbackEvents; event.Loader != 0 || matches.Load() != 1 {
+		t.Errorf("Expected route '0' after one match, got '%d' after %d", event.Loader, matches.Load())
+	}
+	if _, err := New(WithLoaderFor[string, string](nil, source("users"))); err == nil {
+		t.Errorf("Expected a route without a match function to be rejected")
+	}
+}
+
+// Test Case 4: A stampede of misses calls the loader once, then the negative cache answers
+func TestGetOrLoadNeg(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache, err := New(
+		WithListener[string, string](listener),
+		WithNegativeCache[string, string](10, time.Minute),
+		WithLoader[string, string](func(key string) (string, bool) {
+			return "loaded-" + key, key == "absent"
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	absent := func(string) (string, bool) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return "", false
+	}
+
+	const callers = 50
+	var wg sync.WaitGroup
+	var found atomic.Int32
+	call := func() {
+		defer wg.Done()
+		if _, ok := cache.GetOrLoadNeg("absent", absent); ok {
+			found.Add(1)
+		}
+	}
+	wg.Add(callers)
+	go call()
+	<-started
+	for range callers - 1 {
+		go call()
+	}
+	for waiters(cache, "absent", readSlot[string]) < callers-1 {
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+
+	if _, ok := cache.GetOrLoadNeg("absent", absent); ok || found.Load() != 0 {
+		t.Errorf("Expected every call to report the key missing")
+	}
+	if value := calls.Load(); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Get("absent"); value != "loaded-absent" {
+		t.Errorf("Expected the configured loader to still be asked, got '%s'", value)
+	}
+
+	present := func(key string) (string, bool) { return "value-" + key, true }
+	if value, ok := cache.GetOrLoadNeg("present", present); value != "value-present" || !ok {
+		t.Errorf("Expected 'value-present', got '%s' (%v)", value, ok)
+	}
+	if value, ok := cache.GetOrLoadNeg("present", absent); value != "value-present" || !ok {
+		t.Errorf("Expected the cached value, got '%s' (%v)", value, ok)
+	}
+	if value := listener.hitMap["present"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+
+	if _, found := cache.GetOk("other"); found {
+		t.Errorf("Expected the configured loader not to find 'other'")
+	}
+	if value, ok := cache.GetOrLoadNeg("other", present); value != "value-other" || !ok {
+		t.Errorf("Expected 'value-other', got '%s' (%v)", value, ok)
+	}
+}
+
+// waiters returns how many callers wait for the load of key in flight in the
+// slot of its fill picked by slot.
+func waiters(c *LRUCache[string, string], key string, slot func(*fill[string]) **sharedLoad[string]) int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if f, found := c.fills[key]; found && *slot(f) != nil {
+		return (*slot(f)).waiters
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// persistMagic and persistVersion head every file written by SaveTo, so that
+// LoadFrom can refuse data it does not understand instead of misreading it.
+// Version 1 files were entirely gob, header included; since version 2 the
+// header is a text line followed by the entries in the WithCodec encoding, so
+// that JSON files stay readable.
+const (
+	persistMagic   = "in-memory-cache"
+	persistVersion = 2
+)
+
+// persistHeader is the gob-encoded header of a version 1 file.
+type persistHeader struct {
+	Magic   string
+	Version int
+}
+
+// SaveTo writes the entries to w behind a version header, with their absolute
+// expiry times and recency ranks, for LoadFrom to read back. The entries are
+// encoded with the WithCodec codec, gob by default, so keys and values must be
+// encodable by it; with gob, values of interface type need their concrete
+// types registered with gob.Register.
+func (c *LRUCache[K, V]) SaveTo(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%s %d\n", persistMagic, persistVersion); err != nil {
+		return fmt.Errorf("cache: writing snapshot header: %w", err)
+	}
+	if err := c.options.codec.Encode(w, c.snapshotEntries()); err != nil {
+		return fmt.Errorf("cache: encoding entries, keys and values must be encodable by the codec: %w", err)
+	}
+	return nil
+}
+
+// LoadFrom reads entries written by SaveTo with the same codec and stores them
+// as Restore does: entries are rebuilt in their saved recency order, so the
+// same victims are evicted first, and keep their absolute expiry times, so the
+// time between SaveTo and LoadFrom counts against their TTLs and entries that
+// expired in between are skipped. It rejects data that is not a snapshot or
+// was written by an unknown format version.
+func (c *LRUCache[K, V]) LoadFrom(r io.Reader) error {
+	br := bufio.NewReader(r)
+	var entries []PersistedEntry[K, V]
+	var version int
+	if prefix, _ := br.Peek(len(persistMagic)); string(prefix) == persistMagic {
+		if _, err := fmt.Fscanf(br, persistMagic+" %d\n", &version); err != nil {
+			return errors.New("cache: not a cache snapshot")
+		}
+		if version != persistVersion {
+			return fmt.Errorf("cache: unsupported snapshot version %d", version)
+		}
+		decoded, err := c.options.codec.Decode(br)
+		if err != nil {
+			return fmt.Errorf("cache: decoding entries: %w", err)
+		}
+		entries = decoded
+	} else {
+		dec := gob.NewDecoder(br)
+		var header persistHeader
+		if err := dec.Decode(&header); err != nil || header.Magic != persistMagic {
+			return errors.New("cache: not a cache snapshot")
+		}
+		if header.Version != 1 {
+			return fmt.Errorf("cache: unsupported snapshot version %d", header.Version)
+		}
+		if err := dec.Decode(&entries); err != nil {
+			return fmt.Errorf("cache: decoding entries: %w", err)
+		}
+	}
+	c.restore(entries)
+	return nil
+}
+
+// loadFile restores the snapshot at path, if there is one.
+func (c *LRUCache[K, V]) loadFile(path string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.LoadFrom(f)
+}
+
+// saveFile writes a snapshot to path through a temporary file in the same
+// directory, so that a crash midway leaves the previous snapshot intact.
+func (c *LRUCache[K, V]) saveFile(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := c.SaveTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Case 1: SaveTo and LoadFrom keep entries and order and skip expired ones
+func TestSaveToLoadFrom(t *testing.T) {
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("short", "value", 50*time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := cache.SaveTo(&buf); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	loaded := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer loaded.Close()
+	if err := loaded.LoadFrom(&buf); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if keys := loaded.Keys(); len(keys) != 2 || keys[0] != "key2" || keys[1] != "key1" {
+		t.Errorf("Expected [key2 key1], got %v", keys)
+	}
+}
+
+// Test Case 2: Unknown data and versions are rejected
+func TestLoadFromRejectsUnknownData(t *testing.T) {
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+
+	if err := cache.LoadFrom(strings.NewReader("garbage")); err == nil {
+		t.Errorf("Expected an error for data that is not a snapshot")
+	}
+
+	var buf bytes.Buffer
+	_ = gob.NewEncoder(&buf).Encode(persistHeader{Magic: persistMagic, Version: persistVersion + 1})
+	if err := cache.LoadFrom(&buf); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("Expected a version error, got %v", err)
+	}
+}
+
+// Test Case 3: Values gob cannot encode produce a clear error
+func TestSaveToUnencodable(t *testing.T) {
+	type opaque struct{ secret int }
+	cache := NewLRUCache[string, opaque](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+	cache.Put("key", opaque{secret: 1})
+
+	var buf bytes.Buffer
+	if err := cache.SaveTo(&buf); err == nil || !strings.Contains(err.Error(), "encodable") {
+		t.Errorf("Expected an encoding error, got %v", err)
+	}
+}
+
+// Test Case 4: WithPersistence saves on Close and loads on construction
+func TestWithPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	cache := NewLRUCache[string, int](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithPersistence[string, int](path))
+	cache.Put("key1", 1)
+	cache.Put("key2", 2)
+	cache.Close()
+
+	restarted := NewLRUCache[string, int](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithPersistence[string, int](path))
+	defer restarted.Close()
+	if value, found := restarted.GetOk("key1"); !found || value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := restarted.Len(); value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+}
+
+// Test Case 5: WithSnapshotInterval saves periodically and records its timing
+func TestSnapshotInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	cache := NewLRUCache[string, int](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithSnapshotInterval[string, int](50*time.Millisecond, path))
+	cache.Put("key1", 1)
+	time.Sleep(120 * time.Millisecond)
+
+	stats := cache.Stats()
+	if stats.LastSnapshot.IsZero() || stats.SnapshotDuration <= 0 {
+		t.Errorf("Expected a recorded snapshot, got %v taking %v", stats.LastSnapshot, stats.SnapshotDuration)
+	}
+	loaded := NewLRUCache[string, int](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithPersistence[string, int](path))
+	if value := loaded.Get("key1"); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	loaded.Close()
+
+	cache.Put("key2", 2)
+	cache.Close() // Takes a final snapshot
+	restarted := NewLRUCache[string, int](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithPersistence[string, int](path))
+	defer restarted.Close()
+	if value := restarted.Get("key2"); value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+}
+
+// Test Case 6: Snapshot failures reach the error handler
+func TestSnapshotErrorHandler(t *testing.T) {
+	errs := make(chan error, 10)
+	path := filepath.Join(t.TempDir(), "missing", "cache.snapshot")
+	cache := NewLRUCache[string, int](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithSnapshotInterval[string, int](20*time.Millisecond, path),
+		WithErrorHandler[string, int](func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}))
+	defer cache.Close()
+
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), path) {
+			t.Errorf("Expected the error to name the path, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected a snapshot error")
+	}
+}
+
+type account struct {
+	Name    string
+	Balance int
+	Tags    []string
+}
+
+// Test Case 7: Both codecs round-trip struct keys and values through SaveTo
+func TestPersistenceCodecs(t *testing.T) {
+	type accountID struct {
+		Region string
+		Number int
+	}
+	for name, codec := range map[string]Codec[accountID, account]{
+		"gob":  GobCodec[accountID, account]{},
+		"json": JSONCodec[accountID, account]{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cache := NewLRUCache[accountID, account](10, 5*time.Second, nil, quietListener[accountID]{}, 5*time.Second,
+				WithCodec(codec))
+			defer cache.Close()
+			cache.Put(accountID{"eu", 1}, account{Name: "alice", Balance: 10, Tags: []string{"gold"}})
+			cache.Put(accountID{"us", 2}, account{Name: "bob", Balance: 20}, time.Minute)
+
+			var buf bytes.Buffer
+			if err := cache.SaveTo(&buf); err != nil {
+				t.Fatalf("Failed to save: %v", err)
+			}
+			loaded := NewLRUCache[accountID, account](10, time.Hour, nil, quietListener[accountID]{}, 5*time.Second,
+				WithCodec(codec))
+			defer loaded.Close()
+			if err := loaded.LoadFrom(&buf); err != nil {
+				t.Fatalf("Failed to load: %v", err)
+			}
+
+			if keys := loaded.Keys(); len(keys) != 2 || keys[0] != (accountID{"us", 2}) {
+				t.Errorf("Expected us/2 first, got %v", keys)
+			}
+			value, ttl, _ := loaded.GetWithTTL(accountID{"us", 2})
+			if value.Name != "bob" || ttl > time.Minute || ttl < time.Minute-time.Second {
+				t.Errorf("Expected bob with about a minute left, got %+v with %v", value, ttl)
+			}
+			if value := loaded.Get(accountID{"eu", 1}); value.Balance != 10 || len(value.Tags) != 1 || value.Tags[0] != "gold" {
+				t.Errorf("Expected alice's account, got %+v", value)
+			}
+		})
+	}
+}
+
+// Test Case 8: Hand-edited JSON is restored in rank order, and version 1 files still load
+func TestPersistenceFormats(t *testing.T) {
+	cache := NewLRUCache[int, string](10, 5*time.Second, nil, quietListener[int]{}, 5*time.Second,
+		WithCodec[int, string](JSONCodec[int, string]{}))
+	defer cache.Close()
+
+	expires := time.Now().Add(time.Minute).Format(time.RFC3339Nano)
+	file := persistMagic + " 2\n[" +
+		`{"key":1,"value":"one","expiresAt":"` + expires + `","ttl":60000000000,"rank":1},` +
+		`{"key":2,"value":"two","expiresAt":"` + expires + `","ttl":60000000000,"rank":0}]`
+	if err := cache.LoadFrom(strings.NewReader(file)); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if keys := cache.Keys(); len(keys) != 2 || keys[0] != 2 || keys[1] != 1 {
+		t.Errorf("Expected [2 1], got %v", keys)
+	}
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	_ = enc.Encode(persistHeader{Magic: persistMagic, Version: 1})
+	_ = enc.Encode([]PersistedEntry[int, string]{{Key: 3, Value: "three", ExpiresAt: time.Now().Add(time.Minute), TTL: time.Minute}})
+	if err := cache.LoadFrom(&buf); err != nil {
+		t.Fatalf("Failed to load a version 1 file: %v", err)
+	}
+	if value := cache.Get(3); value != "three" {
+		t.Errorf("Expected 'three', got '%s'", value)
+	}
+}
+
+// Test Case 9: LoadFrom keeps the saved eviction order and absolute expiry times
+func TestLoadFromKeepsOrderAndExpiry(t *testing.T) {
+	cache := NewLRUCache[string, string](10, time.Minute, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+	for _, key := range []string{"key1", "key2", "key3", "key4"} {
+		cache.Put(key, "value")
+	}
+	cache.Put("short", "value", 100*time.Millisecond)
+	cache.Get("key1") // Order, most recent first: key1 short key4 key3 key2
+
+	var buf bytes.Buffer
+	if err := cache.SaveTo(&buf); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	time.Sleep(150 * time.Millisecond) // The cache is down for longer than short had left
+
+	listener := &evictionLogListener{}
+	loaded := NewLRUCache[string, string](4, time.Minute, nil, listener, 5*time.Second)
+	defer loaded.Close()
+	if err := loaded.LoadFrom(&buf); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	if _, found := loaded.cache["short"]; found {
+		t.Errorf("Expected 'short' to have expired while the cache was down")
+	}
+	if keys := loaded.Keys(); !slices.Equal(keys, []string{"key1", "key4", "key3", "key2"}) {
+		t.Errorf("Expected [key1 key4 key3 key2], got %v", keys)
+	}
+	if remaining := loaded.cache["key2"].expiry - time.Since(loaded.cache["key2"].lastAccess()); remaining > time.Minute-150*time.Millisecond {
+		t.Errorf("Expected the downtime to count against the TTL, got %v left", remaining)
+	}
+
+	loaded.Put("new1", "value")
+	loaded.Put("new2", "value")
+	if !slices.Equal(listener.evicted, []string{"key2", "key3"}) {
+		t.Errorf("Expected evictions [key2 key3], got %v", listener.evicted)
+	}
+}
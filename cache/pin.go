@@ -0,0 +1,40 @@
+package cache
+
+// Pin keeps the entry for key resident: eviction passes over it, and with
+// WithPinnedNoExpiry so does expiry. Pinned entries still count against the
+// capacity; once every entry is pinned, Put rejects new keys, reporting them
+// as rejections. Pin reports whether key was present and live.
+func (c *LRUCache[K, V]) Pin(key K) bool {
+	key = c.normalize(key)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, found := c.cache[key]
+	if !found || item.expiredAt(c.now()) {
+		return false
+	}
+	if !item.pinned {
+		item.pinned = true
+		item.noExpiry = c.options.pinnedNoExpiry
+		c.pinned++
+	}
+	return true
+}
+
+// Unpin makes the entry for key evictable again. If it was exempt from
+// expiry, its TTL runs from its last use. Unpin reports whether key was
+// pinned.
+func (c *LRUCache[K, V]) Unpin(key K) bool {
+	key = c.normalize(key)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, found := c.cache[key]
+	if !found || !item.pinned {
+		return false
+	}
+	item.pinned = false
+	item.noExpiry = false
+	c.pinned--
+	return true
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// Test Case 1: Eviction passes over pinned entries
+func TestPinSkipsEviction(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second)
+
+	cache.Put("config", "value")
+	cache.Put("key1", "value1")
+	if !cache.Pin("config") {
+		t.Errorf("Expected 'config' to be pinned")
+	}
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3")
+	if value := listener.evictMap["config"]; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+	if value := cache.Stats().Pinned; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+
+	if !cache.Unpin("config") || cache.Unpin("config") {
+		t.Errorf("Expected the first Unpin to succeed and the second to fail")
+	}
+	cache.Put("key4", "value4") // config is the least recently used again
+	if value := listener.evictMap["config"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if cache.Pin("absent") {
+		t.Errorf("Expected Pin of an absent key to fail")
+	}
+}
+
+// Test Case 2: A fully pinned cache rejects new keys
+func TestPinFullCache(t *testing.T) {
+	listener := &rejectingListener{CountingCacheListener: NewCountingCacheListener[string]()}
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second)
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Pin("key1")
+	cache.Pin("key2")
+
+	cache.Put("key3", "value3")
+	if _, found := cache.GetOk("key3"); found {
+		t.Errorf("Expected 'key3' to be rejected")
+	}
+	if len(listener.rejected) != 1 || cache.Stats().Rejections != 1 {
+		t.Errorf("Expected [key3], got %v", listener.rejected)
+	}
+	cache.Put("key1", "updated") // Updates still work
+	if value := cache.Get("key1"); value != "updated" {
+		t.Errorf("Expected 'updated', got '%s'", value)
+	}
+}
+
+// Test Case 3: Pinned entries can be exempted from expiry
+func TestPinNoExpiry(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second,
+		WithPinnedNoExpiry[string, string]())
+
+	cache.Put("key1", "value1", 10*time.Millisecond)
+	cache.Pin("key1")
+	time.Sleep(20 * time.Millisecond) // Wait past the TTL
+	if value := cache.Get("key1"); value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+
+	cache.Unpin("key1")
+	time.Sleep(20 * time.Millisecond) // The TTL applies again
+	if value := cache.Get("key1"); value != "" {
+		t.Errorf("Expected '', got '%s'", value)
+	}
+	if value := listener.expireMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
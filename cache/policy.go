@@ -0,0 +1,158 @@
+package cache
+
+import "time"
+
+// Policy selects the eviction policy of a cache.
+type Policy int
+
+const (
+	// LRU evicts the least recently used entry. It is the default.
+	LRU Policy = iota
+	// LFU evicts the least frequently used entry, breaking ties by recency.
+	LFU
+	// FIFO evicts the oldest inserted entry, regardless of how it is used.
+	FIFO
+	// SLRU is segmented LRU: new entries land in a probation segment and move
+	// to a protected segment on their second use. Eviction takes from probation
+	// first, so a one-off scan cannot flush out the protected working set.
+	SLRU
+	// ARC is the adaptive replacement cache. It splits the capacity between
+	// entries seen once and entries seen again, and shifts the split towards
+	// whichever side recently evicted keys turn out to be requested from.
+	ARC
+	// SecondChance is the clock algorithm, an approximation of LRU. A hit only
+	// sets a reference bit, so hits are served under the read lock, and
+	// eviction sweeps past referenced entries once before taking them.
+	SecondChance
+	// Random evicts a uniformly random entry. It does no bookkeeping on hits,
+	// which suits caches that rarely evict.
+	Random
+	// SampledLRU approximates LRU by sampling a few entries at random on
+	// eviction and evicting the least recently used of them. Hits cost no
+	// more than under Random.
+	SampledLRU
+	// Scored evicts the entry valued least by the score function set with
+	// WithScoring, among a sample of entries.
+	Scored
+)
+
+// evictionPolicy decides the order in which a cache evicts its entries. Every
+// method is called with the cache's write lock held.
+type evictionPolicy[K comparable, V any] interface {
+	// add registers an item that has just been inserted.
+	add(item *CacheItem[K, V])
+	// touch records a read or an overwrite of item.
+	touch(item *CacheItem[K, V])
+	// sharedHits reports whether hits can be recorded with touchShared, which is
+	// called with only the read lock held, instead of touch.
+	sharedHits() bool
+	// touchShared records a read of item under the read lock. It must only
+	// touch state that is safe for concurrent use.
+	touchShared(item *CacheItem[K, V])
+	// remove forgets an item that is leaving the cache for any reason.
+	remove(item *CacheItem[K, V])
+	// victims calls yield with eviction candidates, best candidate first, until
+	// yield returns false. yield must not modify the cache.
+	victims(yield func(*CacheItem[K, V]) bool)
+	// entries calls yield with every item, the one that would be evicted last
+	// first, until yield returns false. It does not change the policy's state.
+	entries(yield func(*CacheItem[K, V]) bool)
+	// clear forgets every item.
+	clear()
+}
+
+// resizablePolicy is implemented by policies whose structure depends on the
+// cache's capacity, so that Resize can keep them in proportion.
+type resizablePolicy interface {
+	resize(capacity int)
+}
+
+func newEvictionPolicy[K comparable, V any](o *options[K, V], capacity int, defaultTTL time.Duration) evictionPolicy[K, V] {
+	switch o.policy {
+	case LFU:
+		return newLFUPolicy[K, V](o.frequencyDecay, o.clock)
+	case FIFO:
+		return newFIFOPolicy[K, V]()
+	case SLRU:
+		return newSLRUPolicy[K, V](capacity, o.probationRatio)
+	case ARC:
+		return newARCPolicy[K, V](capacity, defaultTTL, o.clock)
+	case SecondChance:
+		return newSecondChancePolicy[K, V]()
+	case Random:
+		return newRandomPolicy[K, V]()
+	case SampledLRU:
+		return newSampledLRUPolicy[K, V](o.evictionSamples)
+	case Scored:
+		return newScoredPolicy[K, V](o.evictionSamples, o.score)
+	default:
+		return newLRUPolicy[K, V]()
+	}
+}
+
+// lruPolicy keeps items in a list ordered by recency, most recent at the front.
+type lruPolicy[K comparable, V any] struct {
+	order itemList[K, V]
+}
+
+func newLRUPolicy[K comparable, V any]() *lruPolicy[K, V] {
+	p := &lruPolicy[K, V]{}
+	p.order.init()
+	return p
+}
+
+func (p *lruPolicy[K, V]) add(item *CacheItem[K, V]) {
+	p.order.pushFront(item)
+}
+
+func (p *lruPolicy[K, V]) touch(item *CacheItem[K, V]) {
+	p.order.moveToFront(item)
+}
+
+func (p *lruPolicy[K, V]) sharedHits() bool {
+	return false
+}
+
+func (p *lruPolicy[K, V]) touchShared(*CacheItem[K, V]) {}
+
+func (p *lruPolicy[K, V]) remove(item *CacheItem[K, V]) {
+	p.order.remove(item)
+}
+
+func (p *lruPolicy[K, V]) victims(yield func(*CacheItem[K, V]) bool) {
+	for item := p.order.back(); item != nil; item = p.order.prev(item) {
+		if !yield(item) {
+			return
+		}
+	}
+}
+
+func (p *lruPolicy[K, V]) entries(yield func(*CacheItem[K, V]) bool) {
+	for item := p.order.front(); item != nil; item = p.order.next(item) {
+		if !yield(item) {
+			return
+		}
+	}
+}
+
+func (p *lruPolicy[K, V]) clear() {
+	p.order.init()
+}
+
+// fifoPolicy is an lruPolicy that ignores accesses, leaving items in insertion
+// order.
+type fifoPolicy[K comparable, V any] struct {
+	lruPolicy[K, V]
+}
+
+func newFIFOPolicy[K comparable, V any]() *fifoPolicy[K, V] {
+	p := &fifoPolicy[K, V]{}
+	p.order.init()
+	return p
+}
+
+func (p *fifoPolicy[K, V]) touch(*CacheItem[K, V]) {}
+
+func (p *fifoPolicy[K, V]) sharedHits() bool {
+	return true
+}
@@ -0,0 +1,69 @@
+package cache
+
+import "time"
+
+// PutWithPriority stores value under key like Put and gives the entry
+// priority. Eviction takes entries of the lowest priority present first and
+// only breaks ties by the eviction policy, so an entry that is expensive to
+// recompute can be given a higher priority than the default of zero to
+// outlive cheaper ones. A new entry is always admitted, even if its priority
+// makes it the next to go. A plain Put leaves an entry's priority unchanged.
+func (c *LRUCache[K, V]) PutWithPriority(key K, value V, priority int, ttl ...time.Duration) {
+	key = c.normalize(key)
+	defer c.publishInvalidation(true, key)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.put(key, value, c.expiryFor(ttl))
+	if item, found := c.cache[key]; found {
+		c.setPriority(item, priority)
+	}
+}
+
+// setPriority changes the priority of item, keeping the count of entries at
+// each non-zero priority up to date.
+func (c *LRUCache[K, V]) setPriority(item *CacheItem[K, V], priority int) {
+	c.unprioritize(item)
+	if priority == 0 {
+		return
+	}
+	if c.priorities == nil {
+		c.priorities = make(map[int]int)
+	}
+	item.priority = priority
+	c.priorities[priority]++
+}
+
+// unprioritize returns item to the default priority.
+func (c *LRUCache[K, V]) unprioritize(item *CacheItem[K, V]) {
+	if item.priority == 0 {
+		return
+	}
+	c.priorities[item.priority]--
+	if c.priorities[item.priority] == 0 {
+		delete(c.priorities, item.priority)
+	}
+	item.priority = 0
+}
+
+// lowestPriority returns the lowest priority of any entry, and false when every
+// entry has the default priority, which leaves eviction to the policy alone.
+func (c *LRUCache[K, V]) lowestPriority() (int, bool) {
+	if len(c.priorities) == 0 {
+		return 0, false
+	}
+	prioritized := 0
+	lowest := 0
+	first := true
+	for priority, count := range c.priorities {
+		prioritized += count
+		if first || priority < lowest {
+			lowest = priority
+			first = false
+		}
+	}
+	if prioritized < len(c.cache) {
+		lowest = min(lowest, 0)
+	}
+	return lowest, true
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// Test Case 1: A high-priority entry outlives newer low-priority entries
+func TestPriorityEviction(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](3, 5*time.Second, nil, listener, 5*time.Second)
+	defer cache.Close()
+
+	cache.PutWithPriority("expensive", "value", 10)
+	cache.PutWithPriority("cheap1", "value", -1)
+	cache.Put("plain1", "value")
+	cache.Put("plain2", "value")
+	cache.Put("plain3", "value")
+
+	if value := listener.evictMap["cheap1"]; value != 1 {
+		t.Errorf("Expected the lowest-priority entry to go first, got %v", listener.evictMap)
+	}
+	if value := listener.evictMap["plain1"]; value != 1 {
+		t.Errorf("Expected the least recently used default-priority entry to go next, got %v", listener.evictMap)
+	}
+	if !cache.Contains("expensive") {
+		t.Errorf("Expected the high-priority entry to survive")
+	}
+	if err := cache.checkInvariants(); err != nil {
+		t.Errorf("Expected consistent priorities, got '%v'", err)
+	}
+
+	// Newer default-priority entries keep going before older high-priority ones.
+	cache.Remove("plain2")
+	cache.PutWithPriority("expensive2", "value", 10)
+	cache.Put("plain4", "value")
+	cache.Put("plain5", "value")
+	if value := listener.evictMap["plain3"]; value != 1 {
+		t.Errorf("Expected 'plain3' to be evicted, got %v", listener.evictMap)
+	}
+	if value := listener.evictMap["plain4"]; value != 1 {
+		t.Errorf("Expected 'plain4' to be evicted, got %v", listener.evictMap)
+	}
+	if !cache.Contains("expensive") || !cache.Contains("expensive2") {
+		t.Errorf("Expected both high-priority entries to survive, got %v", cache.Keys())
+	}
+	if err := cache.checkInvariants(); err != nil {
+		t.Errorf("Expected consistent priorities, got '%v'", err)
+	}
+}
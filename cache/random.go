@@ -0,0 +1,62 @@
+package cache
+
+import "math/rand/v2"
+
+// randomPolicy evicts a uniformly random item. It keeps no order at all, only
+// a slice of items for O(1) random selection; each item records its position
+// so that removal can swap the last item into its slot. Hits change nothing,
+// so they are served under the read lock.
+type randomPolicy[K comparable, V any] struct {
+	items []*CacheItem[K, V]
+}
+
+func newRandomPolicy[K comparable, V any]() *randomPolicy[K, V] {
+	return &randomPolicy[K, V]{}
+}
+
+func (p *randomPolicy[K, V]) add(item *CacheItem[K, V]) {
+	item.index = len(p.items)
+	p.items = append(p.items, item)
+}
+
+func (p *randomPolicy[K, V]) touch(*CacheItem[K, V]) {}
+
+func (p *randomPolicy[K, V]) sharedHits() bool {
+	return true
+}
+
+func (p *randomPolicy[K, V]) touchShared(*CacheItem[K, V]) {}
+
+func (p *randomPolicy[K, V]) remove(item *CacheItem[K, V]) {
+	last := len(p.items) - 1
+	p.items[item.index] = p.items[last]
+	p.items[item.index].index = item.index
+	p.items[last] = nil
+	p.items = p.items[:last]
+}
+
+// victims starts at a random item and wraps around, so that vetoed items do
+// not stop the search.
+func (p *randomPolicy[K, V]) victims(yield func(*CacheItem[K, V]) bool) {
+	if len(p.items) == 0 {
+		return
+	}
+	start := rand.IntN(len(p.items))
+	for i := range p.items {
+		if !yield(p.items[(start+i)%len(p.items)]) {
+			return
+		}
+	}
+}
+
+func (p *randomPolicy[K, V]) entries(yield func(*CacheItem[K, V]) bool) {
+	for _, item := range p.items {
+		if !yield(item) {
+			return
+		}
+	}
+}
+
+func (p *randomPolicy[K, V]) clear() {
+	p.items = nil
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Test Case 1: Random eviction keeps the cache within capacity and its slice consistent
+func TestRandomPolicy(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, listener, 5*time.Second,
+		WithPolicy[string, string](Random))
+
+	for i := 0; i < 100; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+		cache.Get(fmt.Sprint("key", i/2))
+		if i%7 == 0 {
+			cache.Remove(fmt.Sprint("key", i-3))
+		}
+	}
+	cache.Put("last", "value")
+
+	if value := cache.Len(); value != 10 {
+		t.Errorf("Expected '10', got '%d'", value)
+	}
+	policy := cache.policy.(*randomPolicy[string, string])
+	if value := len(policy.items); value != 10 {
+		t.Errorf("Expected '10', got '%d'", value)
+	}
+	for index, item := range policy.items {
+		if item.index != index || cache.cache[item.key] != item {
+			t.Errorf("Expected '%s' at index %d, got %d", item.key, index, item.index)
+		}
+	}
+}
+
+// Test Case 2: Vetoed entries are skipped
+func TestRandomPolicyCanEvict(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](3, 5*time.Second, nil, listener, 5*time.Second,
+		WithPolicy[string, string](Random),
+		WithCanEvict[string, string](func(key, value string) bool { return key != "pinned" }))
+
+	cache.Put("pinned", "value")
+	for i := 0; i < 20; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+	if value := cache.Get("pinned"); value != "value" {
+		t.Errorf("Expected 'value', got '%s'", value)
+	}
+	if value := listener.evictMap["pinned"]; value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 3: Expired entries still expire
+func TestRandomPolicyExpiry(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](3, 5*time.Second, nil, listener, 5*time.Second,
+		WithPolicy[string, string](Random))
+
+	cache.Put("key1", "value1", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond) // Wait for expiration
+	if value := cache.Get("key1"); value != "" {
+		t.Errorf("Expected '', got '%s'", value)
+	}
+	if value := listener.expireMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
@@ -0,0 +1,61 @@
+package cache
+
+import "time"
+
+// evictionWindow counts evictions in one-second buckets covering a sliding
+// window, so that the rate can be read without keeping a timestamp per
+// eviction. It is guarded by the cache's lock.
+type evictionWindow struct {
+	buckets []evictionBucket
+}
+
+type evictionBucket struct {
+	second int64
+	count  uint64
+}
+
+func newEvictionWindow(window time.Duration) evictionWindow {
+	return evictionWindow{buckets: make([]evictionBucket, max(int(window/time.Second), 1))}
+}
+
+func (w *evictionWindow) record(now time.Time) {
+	second := now.Unix()
+	bucket := &w.buckets[uint64(second)%uint64(len(w.buckets))]
+	if bucket.second != second {
+		bucket.second, bucket.count = second, 0
+	}
+	bucket.count++
+}
+
+// rate returns the evictions per second over the window ending at now.
+func (w *evictionWindow) rate(now time.Time) float64 {
+	second := now.Unix()
+	var total uint64
+	for _, bucket := range w.buckets {
+		if age := second - bucket.second; age >= 0 && age < int64(len(w.buckets)) {
+			total += bucket.count
+		}
+	}
+	return float64(total) / float64(len(w.buckets))
+}
+
+// FillRatio returns the number of entries as a fraction of the capacity, or
+// zero for an unbounded cache. It can exceed one while entries that cannot be
+// evicted keep the cache over capacity.
+func (c *LRUCache[K, V]) FillRatio() float64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.capacity == 0 {
+		return 0
+	}
+	return float64(len(c.cache)) / float64(c.capacity)
+}
+
+// EvictionRate returns the average number of evictions per second over the
+// window set by WithEvictionRateWindow, a minute by default. Expirations and
+// removals are not evictions and do not count.
+func (c *LRUCache[K, V]) EvictionRate() float64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.evictions.rate(c.now())
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vivekkothari/in-memory-cache/cache/testutil/clocktest"
+)
+
+// Test Case 1: FillRatio follows the number of entries
+func TestFillRatio(t *testing.T) {
+	cache := NewLRUCache[string, int](10, time.Minute, nil, quietListener[string]{}, time.Hour)
+	defer cache.Close()
+
+	for i := 0; i < 10; i++ {
+		cache.Put(fmt.Sprint("key", i), i)
+	}
+	if value := cache.FillRatio(); value != 1 {
+		t.Errorf("Expected '1', got '%v'", value)
+	}
+	for i := 0; i < 5; i++ {
+		cache.Remove(fmt.Sprint("key", i))
+	}
+	if value := cache.FillRatio(); value != 0.5 {
+		t.Errorf("Expected '0.5', got '%v'", value)
+	}
+
+	unbounded := NewLRUCache[string, int](0, time.Minute, nil, quietListener[string]{}, time.Hour)
+	defer unbounded.Close()
+	unbounded.Put("key", 1)
+	if value := unbounded.FillRatio(); value != 0 {
+		t.Errorf("Expected '0' for an unbounded cache, got '%v'", value)
+	}
+}
+
+// Test Case 2: EvictionRate averages evictions over the window
+func TestEvictionRate(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	cache := NewLRUCache[string, int](10, time.Hour, nil, quietListener[string]{}, time.Hour,
+		WithClock[string, int](clock), WithEvictionRateWindow[string, int](10*time.Second))
+	defer cache.Close()
+
+	for i := 0; i < 10; i++ {
+		cache.Put(fmt.Sprint("key", i), i)
+	}
+	if value := cache.EvictionRate(); value != 0 {
+		t.Errorf("Expected '0' before any eviction, got '%v'", value)
+	}
+
+	// Four evictions a second for five seconds: 20 evictions over 10 seconds.
+	next := 10
+	for second := 0; second < 5; second++ {
+		for i := 0; i < 4; i++ {
+			cache.Put(fmt.Sprint("key", next), next)
+			next++
+		}
+		clock.Advance(time.Second)
+	}
+	if value := cache.EvictionRate(); value < 1.6 || value > 2.4 {
+		t.Errorf("Expected about '2' evictions a second, got '%v'", value)
+	}
+
+	clock.Advance(10 * time.Second)
+	if value := cache.EvictionRate(); value != 0 {
+		t.Errorf("Expected '0' once the evictions left the window, got '%v'", value)
+	}
+}
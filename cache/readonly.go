@@ -0,0 +1,39 @@
+package cache
+
+// ReadOnlyCache is the part of a cache that reads it, for code that should
+// see its contents but never change them.
+type ReadOnlyCache[K comparable, V any] interface {
+	// Get behaves exactly as it does on the cache: it counts as a use and
+	// loads misses from the backing store.
+	Get(key K) V
+	Peek(key K) (V, bool)
+	Contains(key K) bool
+	Len() int
+}
+
+type readOnlyCache[K comparable, V any] struct {
+	cache *LRUCache[K, V]
+}
+
+// ReadOnly returns a view of the cache that can read but not write it. The
+// view hides the cache itself, so a type assertion cannot recover write
+// access.
+func (c *LRUCache[K, V]) ReadOnly() ReadOnlyCache[K, V] {
+	return readOnlyCache[K, V]{cache: c}
+}
+
+func (r readOnlyCache[K, V]) Get(key K) V {
+	return r.cache.Get(key)
+}
+
+func (r readOnlyCache[K, V]) Peek(key K) (V, bool) {
+	return r.cache.Peek(key)
+}
+
+func (r readOnlyCache[K, V]) Contains(key K) bool {
+	return r.cache.Contains(key)
+}
+
+func (r readOnlyCache[K, V]) Len() int {
+	return r.cache.Len()
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// Test Case 1: The read-only view reads through and promotes like the cache
+func TestReadOnlyView(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := newTestCache(2, 5*time.Second, listener).(*LRUCache[string, string])
+	defer cache.Close()
+	view := cache.ReadOnly()
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	if value := view.Get("key1"); value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+	if value := view.Get("keyX"); value != "valueX" { // Loaded, evicting key2.
+		t.Errorf("Expected 'valueX', got '%s'", value)
+	}
+	if view.Contains("key2") || !view.Contains("key1") {
+		t.Errorf("Expected Get through the view to have promoted key1, got %v", cache.Keys())
+	}
+	if value, found := view.Peek("key1"); value != "value1" || !found {
+		t.Errorf("Expected 'value1' and true, got '%s' and %t", value, found)
+	}
+	if value := listener.hitMap["key1"]; value != 1 {
+		t.Errorf("Expected Peek not to count as a hit, got '%d'", value)
+	}
+	if value := view.Len(); value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+	if _, ok := view.(Cache[string, string]); ok {
+		t.Errorf("Expected the view not to offer write access")
+	}
+}
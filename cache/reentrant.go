@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+)
+
+// loadChain records the keys whose WithLoaderContext loads are in progress
+// along one chain of nested loads, innermost first.
+type loadChain struct {
+	cache  any
+	key    any
+	parent *loadChain
+}
+
+type loadChainKey struct{}
+
+// withLoading returns ctx marked as being inside the load of key by c.
+func withLoading[K comparable, V any](ctx context.Context, c *LRUCache[K, V], key K) context.Context {
+	parent, _ := ctx.Value(loadChainKey{}).(*loadChain)
+	return context.WithValue(ctx, loadChainKey{}, &loadChain{cache: c, key: key, parent: parent})
+}
+
+// loading reports whether ctx is inside a load of key by c.
+func loading[K comparable, V any](ctx context.Context, c *LRUCache[K, V], key K) bool {
+	chain, _ := ctx.Value(loadChainKey{}).(*loadChain)
+	for ; chain != nil; chain = chain.parent {
+		if chain.cache == any(c) && chain.key == any(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetContext is GetE that passes ctx on to a WithLoaderContext loader and to
+// a ContextListener. It is also how code running inside a WithLoaderContext
+// loader passes on the context the loader was given. A loader can then read
+// other keys from the same cache, which are served or loaded as usual, while
+// a read of a key whose load is already in progress in the same chain, such
+// as the key being loaded itself, fails with ErrLoadCycle instead of
+// recursing forever. Plain Get and GetE cannot see the chain and so cannot
+// detect cycles.
+func (c *LRUCache[K, V]) GetContext(ctx context.Context, key K) (V, error) {
+	var zeroValue V
+	if c.closed.Load() {
+		return zeroValue, ErrClosed
+	}
+	if loading(ctx, c, c.normalize(key)) {
+		return zeroValue, fmt.Errorf("cache: loading key %v: %w", key, ErrLoadCycle)
+	}
+	return c.get(ctx, key)
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newDependentCache(t *testing.T) *LRUCache[string, string] {
+	var cache *LRUCache[string, string]
+	cache, err := New(
+		WithDefaultTTL[string, string](time.Minute),
+		WithLoaderContext(func(ctx context.Context, key string) (string, error) {
+			dependency, ok := map[string]string{"a": "b", "x": "y", "y": "x", "self": "self"}[key]
+			if !ok {
+				return key + "-value", nil
+			}
+			value, err := cache.GetContext(ctx, dependency)
+			if err != nil {
+				return "", err
+			}
+			return key + ":" + value, nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cache
+}
+
+// Test Case 1: A loader can read another key from the same cache
+func TestLoaderReadsOtherKey(t *testing.T) {
+	cache := newDependentCache(t)
+	defer cache.Close()
+
+	if value, err := cache.GetContext(context.Background(), "a"); value != "a:b-value" || err != nil {
+		t.Errorf("Expected 'a:b-value', got '%s' and %v", value, err)
+	}
+	if value, found := cache.GetOk("b"); value != "b-value" || !found {
+		t.Errorf("Expected the dependency to be cached, got '%s'", value)
+	}
+}
+
+// Test Case 2: A load that depends on itself fails instead of recursing
+func TestLoadCycleDetected(t *testing.T) {
+	cache := newDependentCache(t)
+	defer cache.Close()
+
+	for _, key := range []string{"self", "x"} {
+		_, err := cache.GetContext(context.Background(), key)
+		if !errors.Is(err, ErrLoadCycle) || !errors.Is(err, ErrLoadFailed) {
+			t.Errorf("Expected a load cycle for '%s', got %v", key, err)
+		}
+		if cache.Contains(key) {
+			t.Errorf("Expected nothing to be cached for '%s'", key)
+		}
+	}
+}
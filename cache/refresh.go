@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// refreshJob is a refresh-ahead reload of key, whose entry has the TTL prior.
+type refreshJob[K comparable] struct {
+	key   K
+	prior time.Duration
+}
+
+// refresher runs the reloads queued by WithRefreshAhead on a fixed number of
+// workers, so that a burst of entries nearing expiry cannot flood the backing
+// store with loads.
+type refresher[K comparable] struct {
+	workers int
+	queue   chan refreshJob[K]
+	done    sync.WaitGroup
+	// mutex guards pending, the keys queued or being reloaded, which keeps a
+	// key from being queued twice, and stopped, which keeps stop from closing
+	// the queue under a concurrent send.
+	mutex   sync.Mutex
+	pending map[K]struct{}
+	stopped bool
+}
+
+func newRefresher[K comparable](workers, queue int, refresh func(key K, prior time.Duration)) *refresher[K] {
+	r := &refresher[K]{
+		workers: workers,
+		queue:   make(chan refreshJob[K], queue),
+		pending: make(map[K]struct{}),
+	}
+	for range workers {
+		r.done.Add(1)
+		go func() {
+			defer r.done.Done()
+			for job := range r.queue {
+				refresh(job.key, job.prior)
+				r.mutex.Lock()
+				delete(r.pending, job.key)
+				r.mutex.Unlock()
+			}
+		}()
+	}
+	return r
+}
+
+// schedule queues a reload of key unless one is already pending. It never
+// blocks: when the queue is full the reload is skipped and schedule reports
+// false.
+func (r *refresher[K]) schedule(key K, prior time.Duration) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, found := r.pending[key]; found || r.stopped {
+		return true
+	}
+	select {
+	case r.queue <- refreshJob[K]{key: key, prior: prior}:
+		r.pending[key] = struct{}{}
+		return true
+	default:
+		return false
+	}
+}
+
+// queued returns the number of reloads waiting for a worker.
+func (r *refresher[K]) queued() int {
+	return len(r.queue)
+}
+
+// stop waits for the workers to finish every queued reload and stops them.
+func (r *refresher[K]) stop() {
+	r.mutex.Lock()
+	if r.stopped {
+		r.mutex.Unlock()
+		return
+	}
+	r.stopped = true
+	close(r.queue)
+	r.mutex.Unlock()
+	r.done.Wait()
+}
+
+// refreshAhead queues a reload of item if WithRefreshAhead is set and the
+// entry has less than the refresh window left to live. The caller must hold
+// the read or the write lock.
+func (c *LRUCache[K, V]) refreshAhead(item *CacheItem[K, V], now time.Time) {
+	if c.refresher == nil || item.expiry <= 0 || item.noExpiry {
+		return
+	}
+	if item.remaining(now) >= c.options.refreshWindow {
+		return
+	}
+	if !c.refresher.schedule(item.key, item.expiry) {
+		c.stats.refreshSkips.Add(1)
+	}
+}
+
+// refresh reloads key in the background, keeping its TTL. A failed reload
+// leaves the current entry to expire as usual.
+func (c *LRUCache[K, V]) refresh(key K, prior time.Duration) {
+	if _, err := c.reload(key, prior); err != nil && !errors.Is(err, ErrNotFound) {
+		c.onError(fmt.Errorf("cache: refreshing %v: %w", key, err))
+	}
+}
+
+// reload loads key for Refresh or refresh-ahead, with prior as for fetch.
+// Concurrent reloads of key share one load, which alone is reported as a
+// refresh, unless the loader asked for its value not to be cached.
+func (c *LRUCache[K, V]) reload(key K, prior time.Duration) (V, error) {
+	ctx := context.WithValue(context.Background(), reloadKey{}, true)
+	value, err, loaded := c.share(key, reloadSlot[V], func() (V, error) {
+		value, _, _, err := c.fetch(ctx, key, prior)
+		return value, err
+	})
+	if loaded && err == nil {
+		c.onRefresh(key)
+	}
+	if err == ErrDoNotCache {
+		err = nil
+	}
+	return value, err
+}
+
+// reloadKey marks the context of a reload, which goes to the loader even for
+// a spilled key. The spilled copy stays until a reloaded value replaces it.
+type reloadKey struct{}
+
+// reloadSlot picks the reload of a fill for share, which under
+// WithLockStripes misses of the key share as well.
+func reloadSlot[V any](f *fill[V]) **sharedLoad[V] {
+	return &f.reload
+}
+
+// Refresh reloads key from the backing store now, however fresh its entry,
+// for when the data is known to have changed upstream. Readers keep getting
+// the current value until the new one replaces it, instead of paying for the
+// load as they would after a Remove. The new value keeps the entry's TTL if
+// keepTTL is set, never expiring if the entry never did, and otherwise gets
+// the TTL of any other loaded value. If the load fails, or the key is held by
+// WithRemoveHold, the entry, in memory or spilled, is left as it was and
+// Refresh reports false. A successful refresh is reported to a
+// RefreshListener rather than as a load. A value the loader returns with
+// ErrDoNotCache is returned but leaves the entry as it was, and is not
+// reported or counted as a refresh.
+//
+// Concurrent refreshes of key share one load. Under WithLockStripes a
+// refresh also shares loads with misses of the key: a miss that starts while
+// the refresh runs is served its value, and a refresh that starts while a
+// miss is loading settles for what that load returns.
+func (c *LRUCache[K, V]) Refresh(key K, keepTTL bool) (V, bool) {
+	key = c.normalize(key)
+	c.mutex.Lock()
+	var prior time.Duration
+	if item, found := c.cache[key]; found && keepTTL {
+		prior = item.expiry
+		if prior == 0 {
+			prior = keepNoExpiry
+		}
+	}
+	c.mutex.Unlock()
+
+	var zeroValue V
+	if c.held(key) {
+		return zeroValue, false
+	}
+	value, err := c.reload(key, prior)
+	if err != nil {
+		return zeroValue, false
+	}
+	return value, true
+}
+
+// stopRefresher waits for the WithRefreshAhead workers to finish the reloads
+// already queued and stops them.
+func (c *LRUCache[K, V]) stopRefresher() {
+	if c.refresher != nil {
+		c.refresher.stop()
+	}
+}
@@ -0,0 +1,336 @@
+package cache
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vivekkothari/in-memory-cache/cache/testutil/clocktest"
+)
+
+// blockingLoader counts the loads running at once and holds each one until
+// release is closed.
+type blockingLoader struct {
+	started chan string
+	release chan struct{}
+	running atomic.Int32
+	peak    atomic.Int32
+	loads   sync.WaitGroup
+}
+
+func (l *blockingLoader) load(key string) (string, bool) {
+	defer l.loads.Done()
+	running := l.running.Add(1)
+	defer l.running.Add(-1)
+	for {
+		peak := l.peak.Load()
+		if running <= peak || l.peak.CompareAndSwap(peak, running) {
+			break
+		}
+	}
+	l.started <- key
+	<-l.release
+	return "refreshed-" + key, true
+}
+
+func newRefreshingCache(t *testing.T, clock *clocktest.FakeClock, loader *blockingLoader, workers, queue int) *LRUCache[string, string] {
+	t.Helper()
+	cache, err := New(
+		WithClock[string, string](clock),
+		WithDefaultTTL[string, string](10*time.Second),
+		WithCleanupInterval[string, string](24*time.Hour),
+		WithLoader[string, string](loader.load),
+		WithListener[string, string](quietListener[string]{}),
+		WithRefreshAhead[string, string](5*time.Second, workers, queue),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	return cache
+}
+
+// Test Case 1: Refreshes never run on more goroutines than the pool has
+func TestRefreshAheadPoolSize(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	loader := &blockingLoader{started: make(chan string, 10), release: make(chan struct{})}
+	cache := newRefreshingCache(t, clock, loader, 2, 10)
+	defer cache.Close()
+
+	keys := make([]string, 10)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+		cache.Put(keys[i], "value")
+	}
+	clock.Advance(6 * time.Second)
+
+	loader.loads.Add(len(keys))
+	for _, key := range keys {
+		if value := cache.Get(key); value != "value" {
+			t.Errorf("Expected 'value' while the refresh runs, got '%s'", value)
+		}
+	}
+	<-loader.started
+	<-loader.started
+	if stats := cache.Stats(); stats.RefreshWorkers != 2 || stats.RefreshQueued != 8 {
+		t.Errorf("Expected 2 workers and 8 queued refreshes, got %d and %d", stats.RefreshWorkers, stats.RefreshQueued)
+	}
+	close(loader.release)
+	loader.loads.Wait()
+
+	if peak := loader.peak.Load(); peak != 2 {
+		t.Errorf("Expected at most 2 concurrent refreshes, got %d", peak)
+	}
+	cache.Close() // Waits for the last refreshes to be stored.
+	if value, _, _ := cache.GetStale("key9"); value != "refreshed-key9" {
+		t.Errorf("Expected 'refreshed-key9', got '%s'", value)
+	}
+	if ttl, _ := cache.TTL("key9"); ttl != 10*time.Second {
+		t.Errorf("Expected the refresh to keep the 10s TTL, got %v", ttl)
+	}
+}
+
+// Test Case 2: A full queue skips the refresh instead of blocking the read
+func TestRefreshAheadQueueFull(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	loader := &blockingLoader{started: make(chan string, 3), release: make(chan struct{})}
+	cache := newRefreshingCache(t, clock, loader, 1, 1)
+	defer cache.Close()
+
+	for _, key := range []string{"key1", "key2", "key3"} {
+		cache.Put(key, "value")
+	}
+	clock.Advance(6 * time.Second)
+
+	loader.loads.Add(2)
+	cache.Get("key1")
+	<-loader.started // The worker is busy with key1.
+	cache.Get("key2")
+	cache.Get("key2") // Already queued, so neither queued again nor skipped.
+	if value := cache.Get("key3"); value != "value" {
+		t.Errorf("Expected 'value', got '%s'", value)
+	}
+	if stats := cache.Stats(); stats.RefreshQueued != 1 || stats.RefreshSkips != 1 {
+		t.Errorf("Expected 1 queued and 1 skipped refresh, got %d and %d", stats.RefreshQueued, stats.RefreshSkips)
+	}
+	close(loader.release)
+	loader.loads.Wait()
+}
+
+type refreshCountingListener struct {
+	quietListener[string]
+	refreshes atomic.Int32
+}
+
+func (l *refreshCountingListener) OnRefresh(string) { l.refreshes.Add(1) }
+
+// Test Case 3: Refresh replaces a fresh entry and leaves it alone on failure
+func TestRefresh(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	listener := &refreshCountingListener{}
+	var version atomic.Int32
+	failing := atomic.Bool{}
+	cache, err := New(
+		WithClock[string, string](clock),
+		WithDefaultTTL[string, string](10*time.Second),
+		WithCleanupInterval[string, string](24*time.Hour),
+		WithListener[string, string](listener),
+		WithLoader[string, string](func(key string) (string, bool) {
+			if failing.Load() {
+				return "", false
+			}
+			return fmt.Sprintf("%s-v%d", key, version.Add(1)), true
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	cache.Put("key", "cached", 30*time.Second)
+	clock.Advance(20 * time.Second)
+	if value, ok := cache.Refresh("key", true); !ok || value != "key-v1" {
+		t.Errorf("Expected 'key-v1', got '%s' (%v)", value, ok)
+	}
+	if ttl, _ := cache.TTL("key"); ttl != 30*time.Second {
+		t.Errorf("Expected the TTL to be kept, got '%v'", ttl)
+	}
+	if value, ok := cache.Refresh("key", false); !ok || value != "key-v2" {
+		t.Errorf("Expected 'key-v2', got '%s' (%v)", value, ok)
+	}
+	if ttl, _ := cache.TTL("key"); ttl != 10*time.Second {
+		t.Errorf("Expected the default TTL, got '%v'", ttl)
+	}
+
+	failing.Store(true)
+	if _, ok := cache.Refresh("key", true); ok {
+		t.Errorf("Expected a failed refresh to report false")
+	}
+	if value := cache.Get("key"); value != "key-v2" {
+		t.Errorf("Expected 'key-v2', got '%s'", value)
+	}
+	if value := listener.refreshes.Load(); value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+	if stats := cache.Stats(); stats.Refreshes != 2 || stats.Misses != 0 {
+		t.Errorf("Expected 2 refreshes and no misses, got %+v", stats)
+	}
+}
+
+// Test Case 4: A refresh racing a miss shares its load under WithLockStripes
+func TestRefreshSharesLoad(t *testing.T) {
+	loader := &blockingLoader{started: make(chan string, 2), release: make(chan struct{})}
+	var calls atomic.Int32
+	cache, err := New(
+		WithLockStripes[string, string](16),
+		WithListener[string, string](quietListener[string]{}),
+		WithLoader[string, string](func(key string) (string, bool) {
+			calls.Add(1)
+			loader.loads.Add(1)
+			return loader.load(key)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	got := make(chan string)
+	go func() { got <- cache.Get("key") }()
+	<-loader.started
+
+	refreshed := make(chan string)
+	go func() {
+		value, _ := cache.Refresh("key", false)
+		refreshed <- value
+	}()
+	for waiters(cache, "key", reloadSlot[string]) < 1 {
+		runtime.Gosched()
+	}
+	close(loader.release)
+
+	if value := <-got; value != "refreshed-key" {
+		t.Errorf("Expected 'refreshed-key', got '%s'", value)
+	}
+	if value := <-refreshed; value != "refreshed-key" {
+		t.Errorf("Expected 'refreshed-key', got '%s'", value)
+	}
+	if value := calls.Load(); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 5: Keeping the TTL of a never-expiring entry keeps it from expiring
+func TestRefreshKeepsNoExpiry(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	cache, err := New(
+		WithClock[string, string](clock),
+		WithDefaultTTL[string, string](10*time.Second),
+		WithCleanupInterval[string, string](24*time.Hour),
+		WithListener[string, string](quietListener[string]{}),
+		WithLoader[string, string](func(key string) (string, bool) {
+			return "refreshed-" + key, true
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	cache.Put("key", "cached", 0)
+	if value, ok := cache.Refresh("key", true); !ok || value != "refreshed-key" {
+		t.Errorf("Expected 'refreshed-key', got '%s' (%v)", value, ok)
+	}
+	clock.Advance(time.Hour)
+	if ttl, found := cache.TTL("key"); !found || ttl != 0 {
+		t.Errorf("Expected the entry never to expire, got '%v' (%v)", ttl, found)
+	}
+}
+
+// Test Case 6: Concurrent refreshes of a key share one load without stripes
+func TestConcurrentRefreshesShareLoad(t *testing.T) {
+	loader := &blockingLoader{started: make(chan string, 1), release: make(chan struct{})}
+	var calls atomic.Int32
+	cache, err := New(
+		WithListener[string, string](quietListener[string]{}),
+		WithLoader[string, string](func(key string) (string, bool) {
+			calls.Add(1)
+			loader.loads.Add(1)
+			return loader.load(key)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	const refreshes = 8
+	results := make(chan string, refreshes)
+	refresh := func() {
+		value, _ := cache.Refresh("key", false)
+		results <- value
+	}
+	go refresh()
+	<-loader.started
+	for i := 1; i < refreshes; i++ {
+		go refresh()
+	}
+	for waiters(cache, "key", reloadSlot[string]) < refreshes-1 {
+		runtime.Gosched()
+	}
+	close(loader.release)
+
+	for i := 0; i < refreshes; i++ {
+		if value := <-results; value != "refreshed-key" {
+			t.Errorf("Expected 'refreshed-key', got '%s'", value)
+		}
+	}
+	if value := calls.Load(); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if stats := cache.Stats(); stats.Refreshes != 1 {
+		t.Errorf("Expected 1 refresh, got %+v", stats)
+	}
+}
+
+// Test Case 7: A reload the loader asks not to cache is not a refresh
+func TestRefreshDoNotCache(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	listener := &refreshCountingListener{}
+	cache, err := New(
+		WithClock[string, string](clock),
+		WithDefaultTTL[string, string](10*time.Second),
+		WithCleanupInterval[string, string](24*time.Hour),
+		WithListener[string, string](listener),
+		WithRefreshAhead[string, string](5*time.Second, 1, 1),
+		WithLoaderE[string, string](func(key string) (string, error) {
+			return "degraded", ErrDoNotCache
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	cache.Put("key", "cached")
+	if value, ok := cache.Refresh("key", false); !ok || value != "degraded" {
+		t.Errorf("Expected 'degraded', got '%s' (%v)", value, ok)
+	}
+	clock.Advance(6 * time.Second)
+	if value := cache.Get("key"); value != "cached" {
+		t.Errorf("Expected 'cached', got '%s'", value)
+	}
+	cache.Close() // Waits for the refresh-ahead reload.
+
+	if value, _, _ := cache.GetStale("key"); value != "cached" {
+		t.Errorf("Expected 'cached', got '%s'", value)
+	}
+	if value := listener.refreshes.Load(); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+	if stats := cache.Stats(); stats.Refreshes != 0 {
+		t.Errorf("Expected no refreshes, got %+v", stats)
+	}
+}
@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+type evictionLogListener struct {
+	quietListener[string]
+	evicted []string
+}
+
+func (l *evictionLogListener) OnEvict(key string) {
+	l.evicted = append(l.evicted, key)
+}
+
+// Test Case 1: Shrinking evicts the least recently used entries in order
+func TestResizeShrink(t *testing.T) {
+	listener := &evictionLogListener{}
+	cache := NewLRUCache[string, string](5, 5*time.Second, nil, listener, 5*time.Second)
+
+	for i := 1; i <= 5; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+	cache.Get("key1")
+	cache.Get("key3")
+
+	cache.Resize(2)
+	if !slices.Equal(listener.evicted, []string{"key2", "key4", "key5"}) {
+		t.Errorf("Expected [key2 key4 key5], got %v", listener.evicted)
+	}
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"key3", "key1"}) {
+		t.Errorf("Expected [key3 key1], got %v", keys)
+	}
+	if value := cache.Stats().Capacity; value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+
+	cache.Resize(4) // Growing only raises the limit
+	cache.Put("key6", "value")
+	cache.Put("key7", "value")
+	if value := cache.Len(); value != 4 || len(listener.evicted) != 3 {
+		t.Errorf("Expected 4 entries and no new evictions, got %d and %v", value, listener.evicted)
+	}
+}
+
+// Test Case 2: Pinned entries survive a shrink and the cache catches up later
+func TestResizePinned(t *testing.T) {
+	listener := &evictionLogListener{}
+	cache := NewLRUCache[string, string](3, 5*time.Second, nil, listener, 5*time.Second)
+
+	cache.Put("key1", "value")
+	cache.Put("key2", "value")
+	cache.Put("key3", "value")
+	cache.Pin("key1")
+	cache.Pin("key2")
+
+	cache.Resize(1)
+	if !slices.Equal(listener.evicted, []string{"key3"}) || cache.Len() != 2 {
+		t.Errorf("Expected only key3 evicted, got %v", listener.evicted)
+	}
+	cache.Put("key4", "value")
+	if cache.Contains("key4") {
+		t.Errorf("Expected 'key4' to be rejected while every entry is pinned")
+	}
+
+	cache.Unpin("key1")
+	cache.Unpin("key2")
+	cache.Put("key5", "value")
+	if value := cache.Len(); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 3: Sharded caches split the new capacity
+func TestShardedResize(t *testing.T) {
+	cache := NewShardedLRUCache[string, string](4, 40, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+
+	for i := 0; i < 40; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+	cache.Resize(8)
+	if value := cache.Capacity(); value != 8 {
+		t.Errorf("Expected '8', got '%d'", value)
+	}
+	if value := cache.Len(); value > 8 {
+		t.Errorf("Expected at most '8', got '%d'", value)
+	}
+}
+
+// Test Case 4: SLRU keeps its segments in proportion
+func TestResizeSLRU(t *testing.T) {
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithPolicy[string, string](SLRU))
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprint("key", i)
+		cache.Put(key, "value")
+		cache.Get(key)
+	}
+
+	cache.Resize(5)
+	policy := cache.policy.(*slruPolicy[string, string])
+	if policy.protected.len > policy.protectedCapacity || policy.protectedCapacity != 4 {
+		t.Errorf("Expected at most 4 protected entries, got %d of %d", policy.protected.len, policy.protectedCapacity)
+	}
+	if value := cache.Len(); value != 5 {
+		t.Errorf("Expected '5', got '%d'", value)
+	}
+}
+
+// Test Case 5: EvictionCandidates predicts the next evictions
+func TestEvictionCandidates(t *testing.T) {
+	listener := &evictionLogListener{}
+	cache := NewLRUCache[string, string](5, 5*time.Second, nil, listener, 5*time.Second)
+	defer cache.Close()
+
+	for i := 1; i <= 5; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+	cache.Get("key1")
+	cache.Pin("key2")
+
+	candidates := cache.EvictionCandidates(3)
+	if !slices.Equal(candidates, []string{"key3", "key4", "key5"}) {
+		t.Errorf("Expected [key3 key4 key5], got %v", candidates)
+	}
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"key1", "key5", "key4", "key3", "key2"}) {
+		t.Errorf("Expected the order to be unchanged, got %v", keys)
+	}
+
+	for i := 6; i <= 8; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+	if !slices.Equal(listener.evicted, candidates) {
+		t.Errorf("Expected evictions %v, got %v", candidates, listener.evicted)
+	}
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+)
+
+// sampledLRUPolicy approximates LRU the way Redis does: it keeps items in an
+// unordered slice and, to pick a victim, samples a few of them at random and
+// takes the least recently used of the sample. Hits only refresh the item's
+// atomic timestamp, so they are served under the read lock.
+type sampledLRUPolicy[K comparable, V any] struct {
+	randomPolicy[K, V]
+	samples int
+}
+
+func newSampledLRUPolicy[K comparable, V any](samples int) *sampledLRUPolicy[K, V] {
+	return &sampledLRUPolicy[K, V]{samples: max(samples, 1)}
+}
+
+// victims yields the sample, oldest first, then falls back to every item in
+// random order in case WithCanEvict vetoes the whole sample.
+func (p *sampledLRUPolicy[K, V]) victims(yield func(*CacheItem[K, V]) bool) {
+	if len(p.items) == 0 {
+		return
+	}
+	sample := make([]*CacheItem[K, V], min(p.samples, len(p.items)))
+	for i := range sample {
+		sample[i] = p.items[rand.IntN(len(p.items))]
+	}
+	slices.SortFunc(sample, func(a, b *CacheItem[K, V]) int {
+		return cmp.Compare(a.timestamp.Load(), b.timestamp.Load())
+	})
+	for _, item := range sample {
+		if !yield(item) {
+			return
+		}
+	}
+	p.randomPolicy.victims(yield)
+}
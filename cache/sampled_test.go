@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type evictionOrderListener struct {
+	quietListener[int]
+	evicted []int
+}
+
+func (l *evictionOrderListener) OnEvict(key int) {
+	l.evicted = append(l.evicted, key)
+}
+
+// Test Case 1: Sampled eviction prefers older entries on average
+func TestSampledLRUPrefersOlder(t *testing.T) {
+	listener := &evictionOrderListener{}
+	cache := NewSampledLRUCache[int, string](100, 5*time.Second, nil, listener, 5*time.Second)
+
+	for i := 0; i < 100; i++ {
+		cache.Put(i, "value")
+		time.Sleep(10 * time.Microsecond) // Keep timestamps distinct
+	}
+	for i := 100; i < 150; i++ {
+		cache.Put(i, "value")
+	}
+
+	if len(listener.evicted) != 50 {
+		t.Fatalf("Expected '50', got '%d'", len(listener.evicted))
+	}
+	old := 0
+	for _, key := range listener.evicted {
+		if key < 50 {
+			old++
+		}
+	}
+	// Evicting uniformly at random would take about 20 of the oldest half.
+	if old < 30 {
+		t.Errorf("Expected at least 30 evictions from the oldest half, got '%d'", old)
+	}
+}
+
+// Test Case 2: A vetoed sample falls back to the remaining entries
+func TestSampledLRUCanEvict(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewSampledLRUCache[string, string](20, 5*time.Second, nil, listener, 5*time.Second,
+		WithEvictionSamples[string, string](1),
+		WithCanEvict[string, string](func(key, value string) bool { return key == "key7" }))
+
+	for i := 0; i < 20; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+	cache.Put("key20", "value")
+	if value := listener.evictMap["key7"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Len(); value != 20 {
+		t.Errorf("Expected '20', got '%d'", value)
+	}
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// ScoredEntry describes an entry to the score function of WithScoring.
+type ScoredEntry[K comparable] struct {
+	Key        K
+	LastAccess time.Time
+	// Uses counts the entry's insertion, reads and overwrites.
+	Uses uint64
+	// Weight is the weight given by WithMaximumWeight, or zero without it.
+	Weight int64
+}
+
+// scoredPolicy evicts the entry its score function values least. Scoring every
+// entry on each eviction would cost O(n), so like sampledLRUPolicy it scores a
+// sample of consecutive items from a random starting point; a cache no larger
+// than the sample is scored in full, which makes the choice exact.
+type scoredPolicy[K comparable, V any] struct {
+	randomPolicy[K, V]
+	samples int
+	score   func(ScoredEntry[K]) float64
+}
+
+func newScoredPolicy[K comparable, V any](samples int, score func(ScoredEntry[K]) float64) *scoredPolicy[K, V] {
+	return &scoredPolicy[K, V]{samples: max(samples, 1), score: score}
+}
+
+func (p *scoredPolicy[K, V]) add(item *CacheItem[K, V]) {
+	item.uses.Store(1)
+	p.randomPolicy.add(item)
+}
+
+func (p *scoredPolicy[K, V]) touch(item *CacheItem[K, V]) {
+	item.uses.Add(1)
+}
+
+func (p *scoredPolicy[K, V]) touchShared(item *CacheItem[K, V]) {
+	item.uses.Add(1)
+}
+
+// victims yields the sample, lowest score first, then falls back to every item
+// in random order in case WithCanEvict vetoes the whole sample.
+func (p *scoredPolicy[K, V]) victims(yield func(*CacheItem[K, V]) bool) {
+	if len(p.items) == 0 {
+		return
+	}
+	type scored struct {
+		item  *CacheItem[K, V]
+		score float64
+	}
+	sample := make([]scored, min(p.samples, len(p.items)))
+	start := rand.IntN(len(p.items))
+	for i := range sample {
+		item := p.items[(start+i)%len(p.items)]
+		sample[i] = scored{item: item, score: p.score(ScoredEntry[K]{
+			Key:        item.key,
+			LastAccess: item.lastAccess(),
+			Uses:       item.uses.Load(),
+			Weight:     item.weight,
+		})}
+	}
+	slices.SortStableFunc(sample, func(a, b scored) int {
+		return cmp.Compare(a.score, b.score)
+	})
+	for _, s := range sample {
+		if !yield(s.item) {
+			return
+		}
+	}
+	p.randomPolicy.victims(yield)
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// Test Case 1: The entry that is both large and rarely used is evicted
+func TestScoredEviction(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	weights := map[string]int64{"small-hot": 1, "large-hot": 10, "large-cold": 10, "new": 1}
+	cache := NewLRUCache[string, string](3, time.Minute, nil, listener, time.Minute,
+		WithMaximumWeight[string, string](100, func(key, value string) int64 { return weights[key] }),
+		WithEvictionSamples[string, string](10),
+		WithScoring[string, string](func(entry ScoredEntry[string]) float64 {
+			return float64(entry.Uses) / float64(entry.Weight)
+		}))
+	defer cache.Close()
+
+	cache.Put("large-cold", "value") // Oldest, yet still not the victim.
+	cache.Put("small-hot", "value")
+	cache.Put("large-hot", "value")
+	for range 5 {
+		cache.Get("small-hot")
+		cache.Get("large-hot")
+	}
+	cache.Put("new", "value")
+
+	if value := listener.evictMap["large-cold"]; value != 1 {
+		t.Errorf("Expected 'large-cold' to be evicted, got %v", listener.evictMap)
+	}
+	if value := len(listener.evictMap); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
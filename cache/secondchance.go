@@ -0,0 +1,93 @@
+package cache
+
+// secondChancePolicy is the clock algorithm. Items sit on a ring in insertion
+// order and a hit only sets the item's referenced bit, which is atomic so that
+// hits can be served under the read lock. To pick a victim the hand sweeps the
+// ring, clearing set bits and passing over those items, until it reaches an
+// item whose bit is clear.
+type secondChancePolicy[K comparable, V any] struct {
+	ring itemList[K, V]
+	hand *CacheItem[K, V]
+}
+
+func newSecondChancePolicy[K comparable, V any]() *secondChancePolicy[K, V] {
+	p := &secondChancePolicy[K, V]{}
+	p.clear()
+	return p
+}
+
+// after returns the item following item on the ring.
+func (p *secondChancePolicy[K, V]) after(item *CacheItem[K, V]) *CacheItem[K, V] {
+	if next := p.ring.next(item); next != nil {
+		return next
+	}
+	return p.ring.front()
+}
+
+// add places item just behind the hand, so it is the last to be examined.
+func (p *secondChancePolicy[K, V]) add(item *CacheItem[K, V]) {
+	if p.hand == nil {
+		p.ring.pushBack(item)
+		p.hand = item
+		return
+	}
+	p.ring.insertAfter(item, p.hand.prev)
+}
+
+func (p *secondChancePolicy[K, V]) touch(item *CacheItem[K, V]) {
+	item.referenced.Store(true)
+}
+
+func (p *secondChancePolicy[K, V]) sharedHits() bool {
+	return true
+}
+
+func (p *secondChancePolicy[K, V]) touchShared(item *CacheItem[K, V]) {
+	item.referenced.Store(true)
+}
+
+func (p *secondChancePolicy[K, V]) remove(item *CacheItem[K, V]) {
+	if p.hand == item {
+		p.hand = p.after(item)
+		if p.hand == item {
+			p.hand = nil
+		}
+	}
+	p.ring.remove(item)
+}
+
+// victims advances the hand. Two laps are enough to clear every bit, so the
+// sweep always ends.
+func (p *secondChancePolicy[K, V]) victims(yield func(*CacheItem[K, V]) bool) {
+	for steps := 2 * p.ring.len; steps > 0 && p.hand != nil; steps-- {
+		item := p.hand
+		p.hand = p.after(item)
+		if item.referenced.Swap(false) {
+			continue
+		}
+		if !yield(item) {
+			return
+		}
+	}
+}
+
+// entries walks the ring backwards from the item just behind the hand.
+func (p *secondChancePolicy[K, V]) entries(yield func(*CacheItem[K, V]) bool) {
+	if p.hand == nil {
+		return
+	}
+	item := p.hand
+	for range p.ring.len {
+		if item = p.ring.prev(item); item == nil {
+			item = p.ring.back()
+		}
+		if !yield(item) {
+			return
+		}
+	}
+}
+
+func (p *secondChancePolicy[K, V]) clear() {
+	p.ring.init()
+	p.hand = nil
+}
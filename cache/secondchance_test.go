@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test Case 1: A referenced entry gets a second chance
+func TestSecondChanceEviction(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](3, 5*time.Second, nil, listener, 5*time.Second,
+		WithPolicy[string, string](SecondChance))
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3")
+	cache.Get("key1")
+
+	cache.Put("key4", "value4") // The hand clears key1's bit and takes key2
+	if value := listener.evictMap["key2"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	cache.Put("key5", "value5") // The hand continues from key3
+	if value := listener.evictMap["key3"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	cache.Put("key6", "value6") // key1 has used up its second chance
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if keys := cache.Keys(); len(keys) != 3 || keys[0] != "key6" {
+		t.Errorf("Expected 'key6' first, got %v", keys)
+	}
+}
+
+// Test Case 2: Eviction still happens when every entry is referenced
+func TestSecondChanceAllReferenced(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second,
+		WithPolicy[string, string](SecondChance))
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Get("key1")
+	cache.Get("key2")
+
+	cache.Put("key3", "value3")
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Len(); value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+}
+
+// Test Case 3: Concurrent hits and writes are race free
+func TestSecondChanceConcurrent(t *testing.T) {
+	cache := NewLRUCache[string, string](50, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithPolicy[string, string](SecondChance))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := fmt.Sprint("key", (g*i)%100)
+				if i%10 == 0 {
+					cache.Put(key, "value")
+				} else {
+					cache.Get(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if value := cache.Len(); value > 50 {
+		t.Errorf("Expected at most '50', got '%d'", value)
+	}
+	if err := cache.checkInvariants(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Entry is a key and value to store with a TTL, as given to
+// WithInitialEntries. As with Put, a zero TTL means the entry never expires;
+// DefaultTTL gives it the cache's default TTL.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+	TTL   time.Duration
+}
+
+// DefaultTTL is the TTL of an Entry that is to get the cache's default TTL.
+const DefaultTTL time.Duration = -1
+
+// seed stores entries in order before the cache is shared, with the listener
+// silenced.
+func (c *LRUCache[K, V]) seed(entries []Entry[K, V]) {
+	if len(entries) == 0 {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	listener := c.cacheListener
+	c.cacheListener = quietListener[K]{}
+	defer func() { c.cacheListener = listener }()
+	for _, entry := range entries {
+		expiry := entry.TTL
+		if expiry == DefaultTTL {
+			expiry = c.defaultTTL
+		}
+		c.put(c.normalize(entry.Key), entry.Value, expiry)
+	}
+}
+
+// LoadLines stores an entry for every non-blank line of r, such as a fixture
+// file, with decode turning each line into a key and value. Entries are
+// stored as Put stores them, with the default TTL and without consulting the
+// backing store, evicting as needed. It stops at the first line decode
+// rejects, or at a read error, keeping the entries already stored, and
+// returns how many it stored.
+func (c *LRUCache[K, V]) LoadLines(r io.Reader, decode func(line []byte) (K, V, error)) (int, error) {
+	scanner := bufio.NewScanner(r)
+	stored := 0
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		key, value, err := decode(scanner.Bytes())
+		if err != nil {
+			return stored, fmt.Errorf("cache: line %d: %w", line, err)
+		}
+		c.Put(key, value)
+		stored++
+	}
+	if err := scanner.Err(); err != nil {
+		return stored, fmt.Errorf("cache: reading lines: %w", err)
+	}
+	return stored, nil
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Case 1: Seeded entries are present in slice order with their TTLs
+func TestInitialEntries(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second,
+		WithInitialEntries([]Entry[string, string]{
+			{Key: "key1", Value: "value1"},
+			{Key: "key2", Value: "value2", TTL: time.Minute},
+			{Key: "key3", Value: "value3"},
+		}))
+	defer cache.Close()
+
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"key3", "key2"}) {
+		t.Errorf("Expected [key3 key2], got %v", keys)
+	}
+	if len(listener.evictMap) != 0 {
+		t.Errorf("Expected no evictions reported while seeding, got %v", listener.evictMap)
+	}
+	if value := cache.Stats().Evictions; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if _, ttl, _ := cache.GetWithTTL("key2"); ttl <= 5*time.Second {
+		t.Errorf("Expected the seeded TTL of a minute, got %v", ttl)
+	}
+
+	cache.Put("key4", "value4") // Listener events resume after seeding
+	if value := listener.evictMap["key3"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 2: A sharded cache places each seeded entry in its shard
+func TestShardedInitialEntries(t *testing.T) {
+	entries := make([]Entry[int, int], 20)
+	for i := range entries {
+		entries[i] = Entry[int, int]{Key: i, Value: i * 10}
+	}
+	cache := NewShardedLRUCache[int, int](4, 100, 5*time.Second, nil, quietListener[int]{}, 5*time.Second,
+		WithInitialEntries(entries))
+	defer cache.Close()
+
+	if value := cache.Len(); value != 20 {
+		t.Errorf("Expected '20', got '%d'", value)
+	}
+	if value, found := cache.GetOk(7); !found || value != 70 {
+		t.Errorf("Expected '70', got '%d'", value)
+	}
+}
+
+func decodeKeyValue(line []byte) (string, string, error) {
+	key, value, found := strings.Cut(string(line), "=")
+	if !found {
+		return "", "", errors.New("missing '='")
+	}
+	return key, value, nil
+}
+
+// Test Case 3: LoadLines stores each line with the default TTL
+func TestLoadLines(t *testing.T) {
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+
+	fixture := "key1=value1\n\nkey2=value2\nkey3=value3\n"
+	if stored, err := cache.LoadLines(strings.NewReader(fixture), decodeKeyValue); stored != 3 || err != nil {
+		t.Errorf("Expected 3 entries and no error, got %d and '%v'", stored, err)
+	}
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"key3", "key2"}) {
+		t.Errorf("Expected [key3 key2], got %v", keys)
+	}
+	if ttl, found := cache.TTL("key2"); !found || ttl <= 4*time.Second || ttl > 5*time.Second {
+		t.Errorf("Expected the default TTL of 5s, got %v", ttl)
+	}
+
+	stored, err := cache.LoadLines(strings.NewReader("key4=value4\nbroken\nkey5=value5\n"), decodeKeyValue)
+	if stored != 1 || err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected 1 entry and an error for line 2, got %d and '%v'", stored, err)
+	}
+}
+
+// Test Case 4: A zero TTL seeds an entry that never expires, DefaultTTL one with the default
+func TestInitialEntriesDefaultTTL(t *testing.T) {
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithInitialEntries([]Entry[string, string]{
+			{Key: "forever", Value: "value1"},
+			{Key: "default", Value: "value2", TTL: DefaultTTL},
+		}))
+	defer cache.Close()
+
+	if ttl, found := cache.TTL("forever"); !found || ttl != 0 {
+		t.Errorf("Expected the entry never to expire, got %v (%v)", ttl, found)
+	}
+	if ttl, found := cache.TTL("default"); !found || ttl <= 4*time.Second || ttl > 5*time.Second {
+		t.Errorf("Expected the default TTL of 5s, got %v", ttl)
+	}
+}
@@ -0,0 +1,366 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+	"sync"
+	"time"
+)
+
+// ShardedLRUCache partitions keys across independent LRUCache shards, each
+// with its own lock, order list and share of the capacity, so that operations
+// on different shards do not contend. Eviction is per shard, which makes the
+// cache as a whole only approximately LRU.
+type ShardedLRUCache[K comparable, V any] struct {
+	shards          []*LRUCache[K, V]
+	hasher          func(K) uint64
+	cleanupInterval time.Duration
+	stopCleanup     chan struct{}
+	cleanupDone     chan struct{}
+	closeOnce       sync.Once
+}
+
+// NewShardedLRUCache takes the same arguments as NewLRUCache plus the number of
+// shards. The capacity is split evenly across the shards, and a single
+// goroutine runs the cleanup of every shard.
+func NewShardedLRUCache[K comparable, V any](shards int, capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration, opts ...Option[K, V]) *ShardedLRUCache[K, V] {
+	if shards < 1 {
+		panic(fmt.Sprintf("cache: shard count must be at least 1, got %d", shards))
+	}
+	shardCapacity := (capacity + shards - 1) / shards
+	cache := &ShardedLRUCache[K, V]{
+		shards:          make([]*LRUCache[K, V], shards),
+		cleanupInterval: cleanupInterval,
+		stopCleanup:     make(chan struct{}),
+		cleanupDone:     make(chan struct{}),
+	}
+	for i := range cache.shards {
+		shard := newLRUCache(shardCapacity, defaultTTL, backingStore, cacheListener, cleanupInterval, opts...)
+		if shard.options.weigher != nil {
+			shard.options.maxWeight = (shard.options.maxWeight + int64(shards) - 1) / int64(shards)
+		}
+		cache.shards[i] = shard
+	}
+	cache.hasher = cache.shards[0].options.hasher
+	if cache.hasher == nil {
+		cache.hasher = defaultHasher[K]()
+	}
+	for _, entry := range cache.shards[0].options.initialEntries {
+		cache.shardFor(entry.Key).seed([]Entry[K, V]{entry})
+	}
+	ticks, stop := cache.shards[0].options.clock.NewTicker(cache.cleanupInterval)
+	go cache.startCleanup(ticks, stop)
+	return cache
+}
+
+// defaultHasher hashes strings with maphash, scrambles integers with a cheap
+// bit mixer so that sequential ids spread evenly, and falls back to
+// maphash.Comparable for every other key type.
+func defaultHasher[K comparable]() func(K) uint64 {
+	seed := maphash.MakeSeed()
+	return func(key K) uint64 {
+		switch k := any(key).(type) {
+		case string:
+			return maphash.String(seed, k)
+		case int:
+			return mix64(uint64(k))
+		case int8:
+			return mix64(uint64(k))
+		case int16:
+			return mix64(uint64(k))
+		case int32:
+			return mix64(uint64(k))
+		case int64:
+			return mix64(uint64(k))
+		case uint:
+			return mix64(uint64(k))
+		case uint8:
+			return mix64(uint64(k))
+		case uint16:
+			return mix64(uint64(k))
+		case uint32:
+			return mix64(uint64(k))
+		case uint64:
+			return mix64(k)
+		case uintptr:
+			return mix64(uint64(k))
+		default:
+			return maphash.Comparable(seed, key)
+		}
+	}
+}
+
+// mix64 is the splitmix64 finalizer.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func (c *ShardedLRUCache[K, V]) startCleanup(ticks <-chan time.Time, stop func()) {
+	defer close(c.cleanupDone)
+	defer stop()
+
+	for {
+		select {
+		case <-ticks:
+			for _, shard := range c.shards {
+				shard.cleanupExpiredEntries()
+				shard.relieveMemoryPressure()
+			}
+		case <-c.stopCleanup:
+			return
+		}
+	}
+}
+
+// ShardIndex reports which shard key is assigned to.
+func (c *ShardedLRUCache[K, V]) ShardIndex(key K) int {
+	return int(c.hasher(c.shards[0].normalize(key)) % uint64(len(c.shards)))
+}
+
+func (c *ShardedLRUCache[K, V]) shardFor(key K) *LRUCache[K, V] {
+	return c.shards[c.ShardIndex(key)]
+}
+
+func (c *ShardedLRUCache[K, V]) Put(key K, value V, ttl ...time.Duration) {
+	c.shardFor(key).Put(key, value, ttl...)
+}
+
+func (c *ShardedLRUCache[K, V]) Get(key K) V {
+	return c.shardFor(key).Get(key)
+}
+
+func (c *ShardedLRUCache[K, V]) GetOk(key K) (V, bool) {
+	return c.shardFor(key).GetOk(key)
+}
+
+func (c *ShardedLRUCache[K, V]) GetE(key K) (V, error) {
+	return c.shardFor(key).GetE(key)
+}
+
+func (c *ShardedLRUCache[K, V]) GetWithLoader(key K, loader func(K) (V, bool), ttl ...time.Duration) V {
+	return c.shardFor(key).GetWithLoader(key, loader, ttl...)
+}
+
+func (c *ShardedLRUCache[K, V]) GetOrLoadNeg(key K, loader func(K) (V, bool)) (V, bool) {
+	return c.shardFor(key).GetOrLoadNeg(key, loader)
+}
+
+func (c *ShardedLRUCache[K, V]) GetContext(ctx context.Context, key K) (V, error) {
+	return c.shardFor(key).GetContext(ctx, key)
+}
+
+func (c *ShardedLRUCache[K, V]) PutE(key K, value V, ttl ...time.Duration) error {
+	return c.shardFor(key).PutE(key, value, ttl...)
+}
+
+// GetMulti and GetMultiOk behave as they do on LRUCache, looking each key up
+// in its own shard.
+func (c *ShardedLRUCache[K, V]) GetMulti(keys []K) map[K]V {
+	values, _ := c.GetMultiOk(keys)
+	return values
+}
+
+func (c *ShardedLRUCache[K, V]) GetMultiOk(keys []K) (map[K]V, []K) {
+	values := make(map[K]V, len(keys))
+	var missing []K
+	seen := make(map[K]struct{}, len(keys))
+	for _, key := range keys {
+		if _, done := seen[key]; done {
+			continue
+		}
+		seen[key] = struct{}{}
+		if value, found := c.GetOk(key); found {
+			values[key] = value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return values, missing
+}
+
+func (c *ShardedLRUCache[K, V]) PutAndReturnOld(key K, value V, ttl ...time.Duration) (V, bool) {
+	return c.shardFor(key).PutAndReturnOld(key, value, ttl...)
+}
+
+func (c *ShardedLRUCache[K, V]) Refresh(key K, keepTTL bool) (V, bool) {
+	return c.shardFor(key).Refresh(key, keepTTL)
+}
+
+func (c *ShardedLRUCache[K, V]) GetOrSet(key K, value V, ttl ...time.Duration) V {
+	return c.shardFor(key).GetOrSet(key, value, ttl...)
+}
+
+func (c *ShardedLRUCache[K, V]) GetStale(key K) (V, bool, bool) {
+	return c.shardFor(key).GetStale(key)
+}
+
+func (c *ShardedLRUCache[K, V]) Remove(key K) {
+	c.shardFor(key).Remove(key)
+}
+
+// Resize splits capacity evenly across the shards and resizes each of them,
+// with the semantics of LRUCache.Resize.
+func (c *ShardedLRUCache[K, V]) Resize(capacity int) {
+	shardCapacity := (max(capacity, 0) + len(c.shards) - 1) / len(c.shards)
+	for _, shard := range c.shards {
+		shard.Resize(shardCapacity)
+	}
+}
+
+// Capacity returns the sum of the shards' capacities.
+func (c *ShardedLRUCache[K, V]) Capacity() int {
+	total := 0
+	for _, shard := range c.shards {
+		total += shard.Capacity()
+	}
+	return total
+}
+
+func (c *ShardedLRUCache[K, V]) PutTagged(key K, value V, tags []string, ttl ...time.Duration) {
+	c.shardFor(key).PutTagged(key, value, tags, ttl...)
+}
+
+// InvalidateTag removes every entry carrying tag from every shard and returns
+// how many were removed.
+func (c *ShardedLRUCache[K, V]) InvalidateTag(tag string) int {
+	removed := 0
+	for _, shard := range c.shards {
+		removed += shard.InvalidateTag(tag)
+	}
+	return removed
+}
+
+func (c *ShardedLRUCache[K, V]) PutWithPriority(key K, value V, priority int, ttl ...time.Duration) {
+	c.shardFor(key).PutWithPriority(key, value, priority, ttl...)
+}
+
+func (c *ShardedLRUCache[K, V]) LockKey(key K) (unlock func()) {
+	return c.shardFor(key).LockKey(key)
+}
+
+func (c *ShardedLRUCache[K, V]) Pin(key K) bool {
+	return c.shardFor(key).Pin(key)
+}
+
+func (c *ShardedLRUCache[K, V]) Unpin(key K) bool {
+	return c.shardFor(key).Unpin(key)
+}
+
+func (c *ShardedLRUCache[K, V]) Close() {
+	c.closeOnce.Do(func() {
+		for _, shard := range c.shards {
+			shard.closed.Store(true)
+		}
+		close(c.stopCleanup)
+		<-c.cleanupDone
+		for _, shard := range c.shards {
+			shard.stopRefresher()
+			shard.stopListeners()
+		}
+	})
+}
+
+// Len returns the total number of entries across all shards.
+func (c *ShardedLRUCache[K, V]) Len() int {
+	total := 0
+	for _, shard := range c.shards {
+		total += shard.Len()
+	}
+	return total
+}
+
+// Weight returns the total weight of the entries of every shard.
+func (c *ShardedLRUCache[K, V]) Weight() int64 {
+	var total int64
+	for _, shard := range c.shards {
+		total += shard.Weight()
+	}
+	return total
+}
+
+// Keys returns the keys of every shard. Keys are ordered by recency within a
+// shard but not across shards.
+func (c *ShardedLRUCache[K, V]) Keys() []K {
+	var keys []K
+	for _, shard := range c.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+// Clear drops every entry of every shard.
+func (c *ShardedLRUCache[K, V]) Clear() {
+	for _, shard := range c.shards {
+		shard.Clear()
+	}
+}
+
+// FillRatio returns the total number of entries as a fraction of the total
+// capacity, or zero for an unbounded cache.
+func (c *ShardedLRUCache[K, V]) FillRatio() float64 {
+	capacity := c.Capacity()
+	if capacity == 0 {
+		return 0
+	}
+	return float64(c.Len()) / float64(capacity)
+}
+
+// EvictionRate returns the sum of the shards' eviction rates.
+func (c *ShardedLRUCache[K, V]) EvictionRate() float64 {
+	var total float64
+	for _, shard := range c.shards {
+		total += shard.EvictionRate()
+	}
+	return total
+}
+
+// ShardStats returns the statistics of each shard, in shard order, which makes
+// skew caused by hot keys or a poor hash function visible.
+func (c *ShardedLRUCache[K, V]) ShardStats() []CacheStats {
+	stats := make([]CacheStats, len(c.shards))
+	for i, shard := range c.shards {
+		stats[i] = shard.Stats()
+	}
+	return stats
+}
+
+// Stats sums the statistics of all shards.
+func (c *ShardedLRUCache[K, V]) Stats() CacheStats {
+	return c.sumStats((*LRUCache[K, V]).Stats)
+}
+
+// ResetStats sums the statistics of all shards like Stats and zeroes each
+// shard's counters.
+func (c *ShardedLRUCache[K, V]) ResetStats() CacheStats {
+	return c.sumStats((*LRUCache[K, V]).ResetStats)
+}
+
+func (c *ShardedLRUCache[K, V]) sumStats(statsOf func(*LRUCache[K, V]) CacheStats) CacheStats {
+	var total CacheStats
+	for _, shard := range c.shards {
+		stats := statsOf(shard)
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Evictions += stats.Evictions
+		total.Expirations += stats.Expirations
+		total.LoadTimeouts += stats.LoadTimeouts
+		total.DrainDrops += stats.DrainDrops
+		total.Rejections += stats.Rejections
+		total.Refreshes += stats.Refreshes
+		total.Size += stats.Size
+		total.Capacity += stats.Capacity
+		total.Weight += stats.Weight
+		total.MaxWeight += stats.MaxWeight
+		total.Pinned += stats.Pinned
+		total.RefreshWorkers += stats.RefreshWorkers
+		total.RefreshQueued += stats.RefreshQueued
+		total.RefreshSkips += stats.RefreshSkips
+	}
+	return total
+}
@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vivekkothari/in-memory-cache/cache/testutil/clocktest"
+)
+
+// Test Case 1: Put and Get route to the same shard
+func TestShardedPutGet(t *testing.T) {
+	cache := NewShardedLRUCache[string, string](4, 100, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+
+	for i := 0; i < 50; i++ {
+		cache.Put(fmt.Sprint("key", i), fmt.Sprint("value", i))
+	}
+	for i := 0; i < 50; i++ {
+		if value := cache.Get(fmt.Sprint("key", i)); value != fmt.Sprint("value", i) {
+			t.Errorf("Expected 'value%d', got '%s'", i, value)
+		}
+	}
+	if value := cache.Len(); value != 50 {
+		t.Errorf("Expected '50', got '%d'", value)
+	}
+	if value := len(cache.Keys()); value != 50 {
+		t.Errorf("Expected '50', got '%d'", value)
+	}
+
+	cache.Remove("key0")
+	if _, found := cache.GetOk("key0"); found {
+		t.Errorf("Expected 'key0' to be removed")
+	}
+}
+
+// Test Case 2: Stats and Clear aggregate across shards
+func TestShardedStatsAndClear(t *testing.T) {
+	cache := NewShardedLRUCache[int, int](4, 10, 5*time.Second, nil, quietListener[int]{}, 5*time.Second)
+	defer cache.Close()
+
+	for i := 0; i < 100; i++ {
+		cache.Put(i, i)
+	}
+	stats := cache.Stats()
+	if stats.Capacity != 12 {
+		t.Errorf("Expected '12', got '%d'", stats.Capacity)
+	}
+	if uint64(stats.Size)+stats.Evictions != 100 {
+		t.Errorf("Expected size plus evictions to be '100', got %+v", stats)
+	}
+
+	cache.Clear()
+	if value := cache.Len(); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 3: Shards are cleaned up by the shared janitor
+func TestShardedCleanup(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	cache := NewShardedLRUCache[int, int](4, 100, 500*time.Millisecond, nil, quietListener[int]{}, 500*time.Millisecond,
+		WithClock[int, int](clock))
+	for i := 0; i < 20; i++ {
+		cache.Put(i, i)
+	}
+	clock.Advance(time.Second) // Wait for expiration and a cleanup tick
+	// The next tick is only received once the cleanup goroutine has finished
+	// the previous one.
+	clock.Advance(500 * time.Millisecond)
+	if value := cache.Len(); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+	cache.Close()
+	cache.Close()
+}
+
+// Test Case 4: Concurrent access across shards, meant to be run with -race
+func TestShardedConcurrentAccess(t *testing.T) {
+	cache := NewShardedLRUCache[int, int](8, 64, 5*time.Second, nil, quietListener[int]{}, 5*time.Second)
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				cache.Put(g*1000+i, i)
+				cache.Get(g*1000 + i/2)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if value := cache.Len(); value > 64 {
+		t.Errorf("Expected at most '64', got '%d'", value)
+	}
+	for _, shard := range cache.shards {
+		if err := shard.checkInvariants(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// Test Case 5: A custom hasher controls shard placement
+func TestShardedCustomHasher(t *testing.T) {
+	type point struct{ x, y int }
+	cache := NewShardedLRUCache[point, string](2, 10, 5*time.Second, nil, quietListener[point]{}, 5*time.Second,
+		WithHasher[point, string](func(p point) uint64 { return uint64(p.x) }))
+	defer cache.Close()
+
+	cache.Put(point{0, 1}, "even")
+	cache.Put(point{1, 1}, "odd")
+	if value := cache.shards[0].Len(); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.shards[1].Get(point{1, 1}); value != "odd" {
+		t.Errorf("Expected 'odd', got '%s'", value)
+	}
+}
+
+// Test Case 6: Shard stats reveal a hash that concentrates every key
+func TestShardStatsRevealBadHash(t *testing.T) {
+	cache := NewShardedLRUCache[int, int](4, 40, 5*time.Second, nil, quietListener[int]{}, 5*time.Second,
+		WithHasher[int, int](func(int) uint64 { return 0 }))
+	defer cache.Close()
+
+	for i := 0; i < 40; i++ {
+		cache.Put(i, i)
+	}
+
+	stats := cache.ShardStats()
+	if stats[0].Size != 10 || stats[0].Evictions != 30 {
+		t.Errorf("Expected shard 0 to hold 10 entries after 30 evictions, got %+v", stats[0])
+	}
+	for i, shard := range stats[1:] {
+		if shard.Size != 0 {
+			t.Errorf("Expected shard %d to be empty, got %+v", i+1, shard)
+		}
+	}
+}
+
+// Test Case 7: The default hash spreads sequential integer and string keys
+func TestDefaultHasherSpreadsKeys(t *testing.T) {
+	intHasher := defaultHasher[int]()
+	stringHasher := defaultHasher[string]()
+
+	intCounts := make([]int, 8)
+	stringCounts := make([]int, 8)
+	for i := 0; i < 8000; i++ {
+		intCounts[intHasher(i)%8]++
+		stringCounts[stringHasher(fmt.Sprint("key", i))%8]++
+	}
+	for shard := 0; shard < 8; shard++ {
+		if intCounts[shard] < 800 || intCounts[shard] > 1200 {
+			t.Errorf("Expected roughly 1000 integer keys in shard %d, got %d", shard, intCounts[shard])
+		}
+		if stringCounts[shard] < 800 || stringCounts[shard] > 1200 {
+			t.Errorf("Expected roughly 1000 string keys in shard %d, got %d", shard, stringCounts[shard])
+		}
+	}
+}
+
+// Test Case 8: Keys land in the shard chosen by a custom shard function
+func TestShardedShardFunction(t *testing.T) {
+	type order struct {
+		tenant int
+		id     string
+	}
+	cache := NewShardedLRUCache[order, string](4, 40, 5*time.Second, nil, quietListener[order]{}, 5*time.Second,
+		WithHasher[order, string](func(o order) uint64 { return uint64(o.tenant) }))
+	defer cache.Close()
+
+	for tenant := 0; tenant < 4; tenant++ {
+		for i := 0; i < 5; i++ {
+			key := order{tenant, fmt.Sprint("order", i)}
+			cache.Put(key, "value")
+			if shard := cache.ShardIndex(key); shard != tenant {
+				t.Errorf("Expected shard '%d', got '%d'", tenant, shard)
+			}
+		}
+	}
+	for tenant, stats := range cache.ShardStats() {
+		if stats.Size != 5 {
+			t.Errorf("Expected shard %d to hold '5' entries, got '%d'", tenant, stats.Size)
+		}
+	}
+}
+
+// Test Case 9: The default hash spreads struct keys across shards
+func TestShardedDefaultHashStructKeys(t *testing.T) {
+	type order struct {
+		tenant int
+		id     string
+	}
+	cache := NewShardedLRUCache[order, string](4, 4000, 5*time.Second, nil, quietListener[order]{}, 5*time.Second)
+	defer cache.Close()
+
+	for i := 0; i < 1000; i++ {
+		cache.Put(order{1, fmt.Sprint("order", i)}, "value")
+	}
+	for shard, stats := range cache.ShardStats() {
+		if stats.Size < 150 || stats.Size > 350 {
+			t.Errorf("Expected roughly 250 entries in shard %d, got '%d'", shard, stats.Size)
+		}
+	}
+}
+
+// Test Case 10: Close returns only once the cleanup goroutine has stopped
+func TestShardedCloseWaitsForCleanup(t *testing.T) {
+	cache := NewShardedLRUCache[string, string](4, 100, 5*time.Second, nil, quietListener[string]{}, time.Millisecond)
+	cache.Close()
+
+	select {
+	case <-cache.cleanupDone:
+	default:
+		t.Errorf("Expected the cleanup goroutine to have stopped")
+	}
+}
@@ -0,0 +1,105 @@
+package cache
+
+import "math"
+
+const (
+	probationSegment uint8 = iota
+	protectedSegment
+)
+
+// slruPolicy keeps two recency-ordered segments. Items enter probation and are
+// promoted to protected when used again; when protected outgrows its share of
+// the capacity, its least recent item is demoted back to the front of
+// probation. Victims are taken from probation before protected.
+type slruPolicy[K comparable, V any] struct {
+	probation         itemList[K, V]
+	protected         itemList[K, V]
+	protectedCapacity int
+	probationRatio    float64
+}
+
+func newSLRUPolicy[K comparable, V any](capacity int, probationRatio float64) *slruPolicy[K, V] {
+	p := &slruPolicy[K, V]{probationRatio: probationRatio}
+	p.clear()
+	p.resize(capacity)
+	return p
+}
+
+// resize recomputes the protected segment's share and demotes its least
+// recent items if it no longer fits.
+func (p *slruPolicy[K, V]) resize(capacity int) {
+	probationCapacity := max(int(float64(capacity)*p.probationRatio), 1)
+	p.protectedCapacity = max(capacity-probationCapacity, 0)
+	if capacity == 0 {
+		// An unbounded cache has no reason to demote.
+		p.protectedCapacity = math.MaxInt
+	}
+	for p.protected.len > p.protectedCapacity {
+		p.demote()
+	}
+}
+
+func (p *slruPolicy[K, V]) demote() {
+	demoted := p.protected.back()
+	p.protected.remove(demoted)
+	demoted.segment = probationSegment
+	p.probation.pushFront(demoted)
+}
+
+func (p *slruPolicy[K, V]) add(item *CacheItem[K, V]) {
+	item.segment = probationSegment
+	p.probation.pushFront(item)
+}
+
+func (p *slruPolicy[K, V]) touch(item *CacheItem[K, V]) {
+	if item.segment == protectedSegment {
+		p.protected.moveToFront(item)
+		return
+	}
+
+	p.probation.remove(item)
+	item.segment = protectedSegment
+	p.protected.pushFront(item)
+	if p.protected.len > p.protectedCapacity {
+		p.demote()
+	}
+}
+
+func (p *slruPolicy[K, V]) sharedHits() bool {
+	return false
+}
+
+func (p *slruPolicy[K, V]) touchShared(*CacheItem[K, V]) {}
+
+func (p *slruPolicy[K, V]) remove(item *CacheItem[K, V]) {
+	if item.segment == protectedSegment {
+		p.protected.remove(item)
+	} else {
+		p.probation.remove(item)
+	}
+}
+
+func (p *slruPolicy[K, V]) victims(yield func(*CacheItem[K, V]) bool) {
+	for _, segment := range []*itemList[K, V]{&p.probation, &p.protected} {
+		for item := segment.back(); item != nil; item = segment.prev(item) {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+func (p *slruPolicy[K, V]) entries(yield func(*CacheItem[K, V]) bool) {
+	for _, segment := range []*itemList[K, V]{&p.protected, &p.probation} {
+		for item := segment.front(); item != nil; item = segment.next(item) {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+func (p *slruPolicy[K, V]) clear() {
+	p.probation.init()
+	p.protected.init()
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Test Case 1: A hot working set survives a scan
+func TestSLRUResistsScan(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, listener, 5*time.Second,
+		WithPolicy[string, string](SLRU))
+
+	hot := []string{"hot1", "hot2", "hot3", "hot4"}
+	for _, key := range hot {
+		cache.Put(key, "value")
+		cache.Get(key)
+	}
+	for i := 0; i < 100; i++ {
+		cache.Put(fmt.Sprint("scan", i), "value")
+		if i%10 == 0 {
+			for _, key := range hot {
+				cache.Get(key)
+			}
+		}
+	}
+
+	for _, key := range hot {
+		if _, found := cache.GetOk(key); !found {
+			t.Errorf("Expected '%s' to survive the scan", key)
+		}
+		if value := listener.evictMap[key]; value != 0 {
+			t.Errorf("Expected '0', got '%d'", value)
+		}
+	}
+	if value := cache.Len(); value != 10 {
+		t.Errorf("Expected '10', got '%d'", value)
+	}
+}
+
+// Test Case 2: Protected overflow demotes back to probation
+func TestSLRUDemotion(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](4, 5*time.Second, nil, listener, 5*time.Second,
+		WithPolicy[string, string](SLRU), WithProbationRatio[string, string](0.5))
+
+	for _, key := range []string{"key1", "key2", "key3"} {
+		cache.Put(key, "value")
+		cache.Get(key) // Protected holds two entries, so key1 is demoted by key3
+	}
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"key3", "key2", "key1"}) {
+		t.Errorf("Expected [key3 key2 key1], got %v", keys)
+	}
+
+	cache.Put("key4", "value")
+	cache.Put("key5", "value") // Evicts key1, the oldest entry in probation
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 3: TTL and backing store behave as they do for LRU
+func TestSLRUExpiryAndBackingStore(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	backingStore := func(key string) (string, bool) { return "loaded", key == "keyX" }
+	cache := NewLRUCache[string, string](2, 5*time.Second, backingStore, listener, 5*time.Second,
+		WithPolicy[string, string](SLRU))
+
+	cache.Put("key1", "value1", 10*time.Millisecond)
+	cache.Get("key1")
+	time.Sleep(20 * time.Millisecond) // Wait for expiration
+	if value := cache.Get("key1"); value != "" {
+		t.Errorf("Expected '', got '%s'", value)
+	}
+	if value := listener.expireMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Get("keyX"); value != "loaded" {
+		t.Errorf("Expected 'loaded', got '%s'", value)
+	}
+}
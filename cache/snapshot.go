@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// PersistedEntry is the serialised form of a cache entry.
+type PersistedEntry[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+	// ExpiresAt is the zero time for an entry whose TTL is zero, which never
+	// expires.
+	ExpiresAt time.Time     `json:"expiresAt"`
+	TTL       time.Duration `json:"ttl"`
+	// Rank is the entry's recency rank, 0 for the most recently used; entries
+	// are restored in rank order.
+	Rank int `json:"rank"`
+}
+
+// snapshotEntries returns every resident entry, most recently used first.
+func (c *LRUCache[K, V]) snapshotEntries() []PersistedEntry[K, V] {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	entries := make([]PersistedEntry[K, V], 0, len(c.cache))
+	c.policy.entries(func(item *CacheItem[K, V]) bool {
+		value, ok := c.valueOf(item)
+		if !ok {
+			return true
+		}
+		entries = append(entries, PersistedEntry[K, V]{
+			Key:       item.key,
+			Value:     value,
+			ExpiresAt: item.expiresAt(),
+			TTL:       item.expiry,
+			Rank:      len(entries),
+		})
+		return true
+	})
+	return entries
+}
+
+func (c *LRUCache[K, V]) writeSnapshot(w io.Writer) error {
+	return c.Snapshot(w, c.options.codec)
+}
+
+// Snapshot writes every resident entry to w with codec, most recently used
+// first, together with its TTL and the time it is due to expire.
+func (c *LRUCache[K, V]) Snapshot(w io.Writer, codec Codec[K, V]) error {
+	return codec.Encode(w, c.snapshotEntries())
+}
+
+// Restore reads entries written by Snapshot with the same codec and stores
+// them, keeping their recency order, TTLs and expiry times. Entries that have
+// expired since are skipped; entries already in the cache are overwritten.
+func (c *LRUCache[K, V]) Restore(r io.Reader, codec Codec[K, V]) error {
+	entries, err := codec.Decode(r)
+	if err != nil {
+		return err
+	}
+	c.restore(entries)
+	return nil
+}
+
+// restore stores entries in order of their recency ranks, skipping those that
+// have expired.
+func (c *LRUCache[K, V]) restore(entries []PersistedEntry[K, V]) {
+	slices.SortStableFunc(entries, func(a, b PersistedEntry[K, V]) int {
+		return cmp.Compare(a.Rank, b.Rank)
+	})
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.TTL != 0 && !entry.ExpiresAt.After(now) {
+			continue
+		}
+		key := c.normalize(entry.Key)
+		c.put(key, entry.Value, entry.TTL)
+		if item, found := c.cache[key]; found && entry.TTL != 0 {
+			item.touch(entry.ExpiresAt.Add(-entry.TTL))
+		}
+	}
+}
+
+// periodicSnapshot writes the snapshots configured by WithPeriodicSnapshot and
+// WithSnapshotInterval, reporting failures to the error handler.
+func (c *LRUCache[K, V]) periodicSnapshot() {
+	if c.options.snapshotWriter != nil {
+		if err := c.writeToNewWriter(); err != nil {
+			c.onError(fmt.Errorf("cache: periodic snapshot: %w", err))
+		}
+	}
+	if path := c.options.snapshotPath; path != "" {
+		start := c.now()
+		if err := c.saveFile(path); err != nil {
+			c.onError(fmt.Errorf("cache: snapshot to %s: %w", path, err))
+			return
+		}
+		c.stats.snapshotDuration.Store(int64(c.now().Sub(start)))
+		c.stats.lastSnapshot.Store(start.UnixNano())
+	}
+}
+
+func (c *LRUCache[K, V]) writeToNewWriter() error {
+	w, err := c.options.snapshotWriter()
+	if err != nil {
+		return err
+	}
+	err = c.writeSnapshot(w)
+	if closer, ok := w.(io.Closer); ok {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// snapshotBuffers hands out in-memory writers and keeps the content of each
+// one once the cache closes it.
+type snapshotBuffers struct {
+	mutex     sync.Mutex
+	snapshots [][]byte
+}
+
+type snapshotBuffer struct {
+	bytes.Buffer
+	owner *snapshotBuffers
+}
+
+func (b *snapshotBuffer) Close() error {
+	b.owner.mutex.Lock()
+	defer b.owner.mutex.Unlock()
+	b.owner.snapshots = append(b.owner.snapshots, b.Bytes())
+	return nil
+}
+
+func (s *snapshotBuffers) newWriter() (io.Writer, error) {
+	return &snapshotBuffer{owner: s}, nil
+}
+
+func (s *snapshotBuffers) last(t *testing.T) []PersistedEntry[string, string] {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var entries []PersistedEntry[string, string]
+	if err := gob.NewDecoder(bytes.NewReader(s.snapshots[len(s.snapshots)-1])).Decode(&entries); err != nil {
+		t.Fatalf("Failed to decode snapshot: %v", err)
+	}
+	return entries
+}
+
+func (s *snapshotBuffers) count() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.snapshots)
+}
+
+// Test Case 1: Snapshots are written on the interval and on Close
+func TestPeriodicSnapshot(t *testing.T) {
+	snapshots := &snapshotBuffers{}
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second,
+		WithPeriodicSnapshot[string, string](100*time.Millisecond, snapshots.newWriter))
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	time.Sleep(250 * time.Millisecond) // Wait for two snapshots
+
+	if value := snapshots.count(); value != 2 {
+		t.Fatalf("Expected '2' snapshots, got '%d'", value)
+	}
+	entries := snapshots.last(t)
+	if len(entries) != 2 || entries[0].Key != "key2" || entries[1].Value != "value1" {
+		t.Errorf("Expected key2 then key1, got %+v", entries)
+	}
+
+	cache.Put("key3", "value3")
+	cache.Close()
+	if value := snapshots.count(); value != 3 {
+		t.Fatalf("Expected a final snapshot on Close, got '%d' snapshots", value)
+	}
+	if entries := snapshots.last(t); len(entries) != 3 || entries[0].Key != "key3" {
+		t.Errorf("Expected key3 in the final snapshot, got %+v", entries)
+	}
+}
+
+// Test Case 2: Entries, order and TTLs survive a round trip through each codec
+func TestSnapshotCodecs(t *testing.T) {
+	for name, codec := range map[string]Codec[string, string]{
+		"gob":  GobCodec[string, string]{},
+		"json": JSONCodec[string, string]{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+			defer cache.Close()
+			cache.Put("key1", "value1", time.Minute)
+			cache.Put("key2", "value2")
+			cache.Put("gone", "value", 10*time.Millisecond)
+			time.Sleep(20 * time.Millisecond)
+
+			var buf bytes.Buffer
+			if err := cache.Snapshot(&buf, codec); err != nil {
+				t.Fatalf("Failed to write snapshot: %v", err)
+			}
+			restored := NewLRUCache[string, string](10, time.Hour, nil, quietListener[string]{}, 5*time.Second)
+			defer restored.Close()
+			if err := restored.Restore(&buf, codec); err != nil {
+				t.Fatalf("Failed to restore snapshot: %v", err)
+			}
+
+			if keys := restored.Keys(); len(keys) != 2 || keys[0] != "key2" || keys[1] != "key1" {
+				t.Errorf("Expected [key2 key1], got %v", keys)
+			}
+			value, ttl, _ := restored.GetWithTTL("key1")
+			if value != "value1" || ttl > time.Minute || ttl < time.Minute-time.Second {
+				t.Errorf("Expected 'value1' with about a minute left, got '%s' with %v", value, ttl)
+			}
+			if _, ttl, _ := restored.GetWithTTL("key2"); ttl > 5*time.Second || ttl < 4*time.Second {
+				t.Errorf("Expected about 5s left, got %v", ttl)
+			}
+		})
+	}
+}
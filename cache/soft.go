@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+// heapObjectsMetric is the runtime metric compared against the
+// WithMemoryPressure limit: the bytes held by live and not yet swept heap
+// objects. Reading it does not stop the world, unlike runtime.ReadMemStats.
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// softShedFraction is the share of its entries a NewSoftCache sheds each time
+// the heap is found over the limit.
+const softShedFraction = 0.5
+
+// NewSoftCache takes the same arguments as NewLRUCache, preceded by a heap
+// limit in bytes, and returns a cache whose entries give way to memory
+// pressure: whenever the cleanup goroutine finds the Go heap above heapLimit,
+// the cache evicts half of its entries, least recently used first. Evicted
+// entries are reloaded from the backing store on their next access, so the
+// cache behaves as if it held soft references to its values. How quickly the
+// memory is returned depends on the garbage collector.
+func NewSoftCache[K comparable, V any](heapLimit uint64, capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration, opts ...Option[K, V]) *LRUCache[K, V] {
+	opts = append([]Option[K, V]{WithMemoryPressure[K, V](heapLimit, softShedFraction)}, opts...)
+	return NewLRUCache(capacity, defaultTTL, backingStore, cacheListener, cleanupInterval, opts...)
+}
+
+// heapInUse returns the bytes currently held by heap objects.
+func heapInUse() uint64 {
+	sample := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// relieveMemoryPressure evicts the WithMemoryPressure share of the entries if
+// the heap is over the limit, and reports how many it evicted.
+func (c *LRUCache[K, V]) relieveMemoryPressure() int {
+	if c.options.heapLimit == 0 || c.options.heapInUse() <= c.options.heapLimit {
+		return 0
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	before := len(c.cache)
+	keep := before - int(float64(before)*c.options.shedFraction+0.5)
+	for len(c.cache) > keep {
+		victim := c.victim(nil)
+		if victim == nil {
+			break
+		}
+		c.evictItem(victim)
+	}
+	return before - len(c.cache)
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vivekkothari/in-memory-cache/cache/testutil/clocktest"
+)
+
+// Test Case 1: Memory pressure sheds the least recently used half, which reloads on access
+func TestSoftCacheShedsUnderPressure(t *testing.T) {
+	var heap atomic.Uint64
+	heap.Store(100)
+	loads := 0
+	backingStore := func(key string) (string, bool) {
+		loads++
+		return "reloaded", true
+	}
+	listener := NewCountingCacheListener[string]()
+	cache := NewSoftCache[string, string](1000, 0, 5*time.Second, backingStore, listener, time.Hour,
+		func(o *options[string, string]) { o.heapInUse = heap.Load })
+	defer cache.Close()
+
+	for i := 0; i < 8; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+	if value := cache.relieveMemoryPressure(); value != 0 {
+		t.Errorf("Expected no evictions below the limit, got '%d'", value)
+	}
+
+	heap.Store(2000)
+	if value := cache.relieveMemoryPressure(); value != 4 {
+		t.Errorf("Expected '4' evictions, got '%d'", value)
+	}
+	if value := listener.evictMap["key0"]; value != 1 {
+		t.Errorf("Expected the oldest key to be evicted, got '%d'", value)
+	}
+	if value := cache.Get("key7"); value != "value" {
+		t.Errorf("Expected 'value', got '%s'", value)
+	}
+	if value := cache.Get("key0"); value != "reloaded" || loads != 1 {
+		t.Errorf("Expected 'reloaded' after one load, got '%s' after %d", value, loads)
+	}
+}
+
+// Test Case 2: The cleanup goroutine keeps shedding while the heap stays over the limit
+func TestSoftCacheBackground(t *testing.T) {
+	var heap atomic.Uint64
+	heap.Store(2000)
+	cache := NewSoftCache[int, int](1000, 0, 5*time.Second, nil, quietListener[int]{}, 10*time.Millisecond,
+		func(o *options[int, int]) { o.heapInUse = heap.Load })
+	defer cache.Close()
+
+	for i := 0; i < 64; i++ {
+		cache.Put(i, i)
+	}
+	deadline := time.Now().Add(time.Second)
+	for cache.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if value := cache.Len(); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 3: A sharded cache sheds every shard under memory pressure
+func TestShardedMemoryPressure(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	var heap atomic.Uint64
+	heap.Store(2000)
+	cache := NewShardedLRUCache[int, int](4, 0, 5*time.Second, nil, quietListener[int]{}, time.Second,
+		WithClock[int, int](clock),
+		WithMemoryPressure[int, int](1000, 0.5),
+		func(o *options[int, int]) { o.heapInUse = heap.Load })
+	defer cache.Close()
+
+	for i := 0; i < 64; i++ {
+		cache.Put(i, i)
+	}
+	clock.Advance(time.Second)
+	clock.Advance(time.Second) // Handed over only once the first tick is done.
+	if value := cache.Len(); value > 32 {
+		t.Errorf("Expected at most 32 entries left, got %d", value)
+	}
+}
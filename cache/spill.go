@@ -0,0 +1,216 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SpillStore holds the entries WithSpill moves out of memory instead of
+// discarding them on eviction, typically on disk. Put stores the value of
+// key, replacing any earlier one, Get returns it and Remove deletes it;
+// removing a key the store does not hold is not an error. DirSpill keeps each
+// entry in its own file.
+type SpillStore[K comparable, V any] interface {
+	Put(key K, value V) error
+	Get(key K) (V, bool)
+	Remove(key K)
+}
+
+// spilledEntry is what the cache remembers of an entry it spilled: the TTL it
+// had, when it is due to expire, in Unix nanoseconds, or zero if never, and
+// its tags, so that InvalidateTag reaches it on disk too.
+type spilledEntry struct {
+	ttl      time.Duration
+	deadline int64
+	tags     []string
+}
+
+// spill writes item, which is being evicted and carried tags, to the WithSpill
+// store. Expired entries are not worth keeping and are dropped as before. The
+// caller must hold the write lock.
+func (c *LRUCache[K, V]) spill(item *CacheItem[K, V], tags []string) {
+	if c.options.spill == nil {
+		return
+	}
+	now := c.now()
+	if item.expiredAt(now) {
+		return
+	}
+	value, ok := c.valueOf(item)
+	if !ok {
+		return
+	}
+	if err := c.options.spill.Put(item.key, value); err != nil {
+		c.onError(fmt.Errorf("cache: spilling %v: %w", item.key, err))
+		return
+	}
+	entry := spilledEntry{ttl: item.expiry, tags: tags}
+	if item.expiry != 0 && !item.noExpiry {
+		entry.deadline = now.Add(item.remaining(now)).UnixNano()
+	}
+	if c.spilled == nil {
+		c.spilled = make(map[K]spilledEntry)
+	}
+	c.spilled[item.key] = entry
+}
+
+// unspill takes key back out of the WithSpill store, returning its value and
+// what else was kept of its entry, and false if the key was not spilled or its
+// entry has expired since.
+func (c *LRUCache[K, V]) unspill(key K) (V, spilledEntry, bool) {
+	var zeroValue V
+	if c.options.spill == nil {
+		return zeroValue, spilledEntry{}, false
+	}
+	c.mutex.Lock()
+	entry, found := c.spilled[key]
+	delete(c.spilled, key)
+	c.mutex.Unlock()
+	if !found {
+		return zeroValue, spilledEntry{}, false
+	}
+
+	defer c.options.spill.Remove(key)
+	if entry.deadline != 0 && c.now().UnixNano() >= entry.deadline {
+		return zeroValue, spilledEntry{}, false
+	}
+	value, found := c.options.spill.Get(key)
+	if !found {
+		return zeroValue, spilledEntry{}, false
+	}
+	return value, entry, true
+}
+
+// spilledValue returns the value spilled for key, unless its entry has
+// expired, leaving it in the store. The caller must hold the lock.
+func (c *LRUCache[K, V]) spilledValue(key K) (V, bool) {
+	var zeroValue V
+	entry, found := c.spilled[key]
+	if !found || (entry.deadline != 0 && c.now().UnixNano() >= entry.deadline) {
+		return zeroValue, false
+	}
+	return c.options.spill.Get(key)
+}
+
+// endUnspill stores the value unspill took back for the load f of key, with
+// the entry's TTL and tags, unless the key was written or removed meanwhile.
+func (c *LRUCache[K, V]) endUnspill(key K, f *fill[V], value V, entry spilledEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.finishFill(key, f) || !c.options.cacheBackingResults {
+		return
+	}
+	c.put(key, value, entry.ttl)
+	if item, found := c.cache[key]; found {
+		c.tag(item, entry.tags)
+	}
+}
+
+// dropSpilled deletes the spilled copy of key, if there is one, so that it
+// cannot come back after key was written or removed, and reports whether
+// there was one. The caller must hold the write lock.
+func (c *LRUCache[K, V]) dropSpilled(key K) bool {
+	if _, found := c.spilled[key]; !found {
+		return false
+	}
+	delete(c.spilled, key)
+	c.options.spill.Remove(key)
+	return true
+}
+
+// spilledKeys returns the spilled keys that match. The caller must hold the
+// lock.
+func (c *LRUCache[K, V]) spilledKeys(match func(key K, entry spilledEntry) bool) []K {
+	var keys []K
+	for key, entry := range c.spilled {
+		if match(key, entry) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// dropAllSpilled deletes every spilled entry, or with expiredOnly just those
+// that have expired. The caller must hold the write lock.
+func (c *LRUCache[K, V]) dropAllSpilled(expiredOnly bool) {
+	now := c.now().UnixNano()
+	for key, entry := range c.spilled {
+		if !expiredOnly || (entry.deadline != 0 && now >= entry.deadline) {
+			delete(c.spilled, key)
+			c.options.spill.Remove(key)
+		}
+	}
+}
+
+// DirSpill is a SpillStore that keeps each entry in a gob file of its own in
+// a directory, named after a hash of the key. Keys and values must be
+// encodable by gob.
+type DirSpill[K comparable, V any] struct {
+	dir string
+}
+
+// dirSpillEntry is the content of a DirSpill file. The key is kept alongside
+// the value so that a hash collision reads as a miss rather than as another
+// key's value.
+type dirSpillEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// NewDirSpill returns a DirSpill storing its files in dir, which is created if
+// it does not exist.
+func NewDirSpill[K comparable, V any](dir string) (*DirSpill[K, V], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cache: creating spill directory: %w", err)
+	}
+	return &DirSpill[K, V]{dir: dir}, nil
+}
+
+func (s *DirSpill[K, V]) path(key K) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%#v", key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:16])+".gob")
+}
+
+// Put writes the entry to a temporary file and renames it into place, so that
+// Get never sees a partly written file.
+func (s *DirSpill[K, V]) Put(key K, value V) error {
+	f, err := os.CreateTemp(s.dir, ".spill-*")
+	if err != nil {
+		return err
+	}
+	err = gob.NewEncoder(f).Encode(dirSpillEntry[K, V]{Key: key, Value: value})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+func (s *DirSpill[K, V]) Get(key K) (V, bool) {
+	var zeroValue V
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		return zeroValue, false
+	}
+	defer f.Close()
+	var entry dirSpillEntry[K, V]
+	if err := gob.NewDecoder(f).Decode(&entry); err != nil || entry.Key != key {
+		return zeroValue, false
+	}
+	return entry.Value, true
+}
+
+func (s *DirSpill[K, V]) Remove(key K) {
+	os.Remove(s.path(key))
+}
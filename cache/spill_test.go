@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vivekkothari/in-memory-cache/cache/testutil/clocktest"
+)
+
+func newSpillingCache(t *testing.T, clock *clocktest.FakeClock, listener CacheListener[string]) *LRUCache[string, string] {
+	t.Helper()
+	store, err := NewDirSpill[string, string](t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	cache, err := New(
+		WithCapacity[string, string](2),
+		WithClock[string, string](clock),
+		WithCleanupInterval[string, string](24*time.Hour),
+		WithListener[string, string](listener),
+		WithLoader[string, string](func(key string) (string, bool) { return "loaded-" + key, true }),
+		WithSpill[string, string](store),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	return cache
+}
+
+// Test Case 1: An evicted entry is read back from disk and promoted
+func TestSpillPromotesOnAccess(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	listener := NewCountingCacheListener[string]()
+	cache := newSpillingCache(t, clock, listener)
+	defer cache.Close()
+
+	cache.Put("key1", "value1", time.Minute)
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3") // Evicts key1 to disk.
+	if cache.Contains("key1") || listener.evictMap["key1"] != 1 {
+		t.Fatalf("Expected key1 to be evicted")
+	}
+
+	clock.Advance(10 * time.Second)
+	if value := cache.Get("key1"); value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+	if !cache.Contains("key1") {
+		t.Errorf("Expected key1 to be back in memory")
+	}
+	if ttl, _ := cache.TTL("key1"); ttl != time.Minute {
+		t.Errorf("Expected '%v', got '%v'", time.Minute, ttl)
+	}
+	if err := cache.checkInvariants(); err != nil {
+		t.Errorf("Expected no error, got '%v'", err)
+	}
+
+	// key2 went to disk to make room, and comes back the same way.
+	if value := cache.Get("key2"); value != "value2" {
+		t.Errorf("Expected 'value2', got '%s'", value)
+	}
+}
+
+// Test Case 2: Writes, removals and expiry keep stale copies from coming back
+func TestSpillDropsStaleCopies(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	cache := newSpillingCache(t, clock, quietListener[string]{})
+	defer cache.Close()
+
+	cache.Put("removed", "old")
+	cache.Put("expiring", "old", time.Second)
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2") // Both spilled now.
+	cache.Remove("removed")
+	clock.Advance(2 * time.Second)
+
+	if value := cache.Get("removed"); value != "loaded-removed" {
+		t.Errorf("Expected 'loaded-removed', got '%s'", value)
+	}
+	if value := cache.Get("expiring"); value != "loaded-expiring" {
+		t.Errorf("Expected 'loaded-expiring', got '%s'", value)
+	}
+
+	cache.Clear()
+	entries, err := os.ReadDir(cache.options.spill.(*DirSpill[string, string]).dir)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected Clear to empty the spill directory, got %d files (%v)", len(entries), err)
+	}
+}
+
+// Test Case 3: GetAndRemove takes a spilled entry back and reports its removal
+func TestSpillGetAndRemove(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	listener := NewCountingCacheListener[string]()
+	cache := newSpillingCache(t, clock, listener)
+	defer cache.Close()
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3") // Spills key1.
+	if value, found := cache.GetAndRemove("key1"); !found || value != "value1" {
+		t.Errorf("Expected 'value1', got '%s' (%v)", value, found)
+	}
+	if value := listener.removeMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Get("key1"); value != "loaded-key1" {
+		t.Errorf("Expected 'loaded-key1', got '%s'", value)
+	}
+}
+
+// Test Case 4: RemoveAll, RemovePrefix and InvalidateTag reach spilled entries, tags included
+func TestSpillRemovalPaths(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	listener := NewCountingCacheListener[string]()
+	cache := newSpillingCache(t, clock, listener)
+	defer cache.Close()
+
+	cache.Put("listed", "value")
+	cache.Put("user:1", "value")
+	cache.PutTagged("tagged", "value", []string{"group"})
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2") // All but key1 and key2 are spilled.
+
+	if removed := cache.RemoveAll([]string{"listed"}); removed != 1 {
+		t.Errorf("Expected '1', got '%d'", removed)
+	}
+	if removed := RemovePrefix(cache, "user:"); removed != 1 {
+		t.Errorf("Expected '1', got '%d'", removed)
+	}
+	if removed := cache.InvalidateTag("group"); removed != 1 {
+		t.Errorf("Expected '1', got '%d'", removed)
+	}
+	for _, key := range []string{"listed", "user:1", "tagged"} {
+		if value := listener.removeMap[key]; value != 1 {
+			t.Errorf("Expected '1' removal of %s, got '%d'", key, value)
+		}
+		if value := cache.Get(key); value != "loaded-"+key {
+			t.Errorf("Expected 'loaded-%s', got '%s'", key, value)
+		}
+	}
+}
+
+// Test Case 5: A failed Refresh keeps the spilled copy; a successful one replaces it
+func TestSpillRefresh(t *testing.T) {
+	store, err := NewDirSpill[string, string](t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	var found atomic.Bool
+	cache, err := New(
+		WithCapacity[string, string](2),
+		WithCleanupInterval[string, string](24*time.Hour),
+		WithListener[string, string](quietListener[string]{}),
+		WithLoader[string, string](func(key string) (string, bool) { return "loaded-" + key, found.Load() }),
+		WithSpill[string, string](store),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	cache.Put("key1", "value1")
+	cache.Put("key2", "value2")
+	cache.Put("key3", "value3") // Spills key1.
+	if _, ok := cache.Refresh("key1", false); ok {
+		t.Errorf("Expected the refresh of a key the loader lacks to fail")
+	}
+	if value, _ := cache.GetAndRemove("key1"); value != "value1" {
+		t.Errorf("Expected the spilled 'value1' to survive, got '%s'", value)
+	}
+
+	cache.Put("key4", "value4")
+	cache.Put("key5", "value5") // Spills key3.
+	found.Store(true)
+	if value, ok := cache.Refresh("key3", false); !ok || value != "loaded-key3" {
+		t.Errorf("Expected 'loaded-key3', got '%s' (%v)", value, ok)
+	}
+	if value := cache.Get("key3"); value != "loaded-key3" {
+		t.Errorf("Expected 'loaded-key3', got '%s'", value)
+	}
+	if err := cache.checkInvariants(); err != nil {
+		t.Errorf("Expected no error, got '%v'", err)
+	}
+}
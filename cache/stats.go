@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// CacheStats is a point-in-time view of a cache's counters.
+type CacheStats struct {
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`
+	Expirations uint64 `json:"expirations"`
+	// LoadTimeouts counts backing-store loads abandoned after the load timeout.
+	LoadTimeouts uint64 `json:"loadTimeouts"`
+	// DrainDrops counts expired entries dropped because the channel given to
+	// WithDrainExpired was full.
+	DrainDrops uint64 `json:"drainDrops"`
+	// Rejections counts new keys turned away by the admission filter or for
+	// being heavier than the maximum weight.
+	Rejections uint64 `json:"rejections"`
+	// Refreshes counts entries reloaded by Refresh or WithRefreshAhead.
+	Refreshes uint64 `json:"refreshes"`
+	Size      int    `json:"size"`
+	Capacity  int    `json:"capacity"`
+	// Weight is the total weight of the entries and MaxWeight its bound; both
+	// are zero unless WithMaximumWeight is set.
+	Weight    int64 `json:"weight"`
+	MaxWeight int64 `json:"maxWeight"`
+	// Pinned is the number of pinned entries.
+	Pinned int `json:"pinned"`
+	// RefreshWorkers is the size of the WithRefreshAhead pool, RefreshQueued
+	// the number of reloads waiting for it and RefreshSkips the number skipped
+	// because the queue was full.
+	RefreshWorkers int    `json:"refreshWorkers"`
+	RefreshQueued  int    `json:"refreshQueued"`
+	RefreshSkips   uint64 `json:"refreshSkips"`
+	// SnapshotDuration is how long the last successful WithSnapshotInterval
+	// snapshot took, and LastSnapshot when it started; both are zero until
+	// one succeeds.
+	SnapshotDuration time.Duration `json:"snapshotDuration"`
+	LastSnapshot     time.Time     `json:"lastSnapshot"`
+}
+
+// EntryInfo describes a single resident entry without exposing its value.
+type EntryInfo[K comparable] struct {
+	Key        K         `json:"key"`
+	LastAccess time.Time `json:"lastAccess"`
+	// ExpiresAt is the zero time for an entry that never expires.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type counters struct {
+	hits         atomic.Uint64
+	misses       atomic.Uint64
+	evictions    atomic.Uint64
+	expirations  atomic.Uint64
+	loadTimeouts atomic.Uint64
+	drainDrops   atomic.Uint64
+	rejections   atomic.Uint64
+	cleanups     atomic.Uint64
+	refreshSkips atomic.Uint64
+	refreshes    atomic.Uint64
+	// snapshotDuration and lastSnapshot describe the last successful
+	// WithSnapshotInterval snapshot, in nanoseconds.
+	snapshotDuration atomic.Int64
+	lastSnapshot     atomic.Int64
+}
+
+func (c *LRUCache[K, V]) onHit(ctx context.Context, key K) {
+	c.stats.hits.Add(1)
+	c.notifyContext(ctx, EventHit, key)
+	c.publish(EventHit, key)
+}
+
+func (c *LRUCache[K, V]) onMiss(ctx context.Context, key K) {
+	c.stats.misses.Add(1)
+	c.publish(EventMiss, key)
+	if _, ok := c.cacheListener.(LoadListener[K]); ok {
+		return // Reported by fetchFromBackingStore once the load is done.
+	}
+	c.notifyContext(ctx, EventMiss, key)
+}
+
+func (c *LRUCache[K, V]) onEvict(key K) {
+	c.stats.evictions.Add(1)
+	c.notify(EventEvict, key)
+	c.publish(EventEvict, key)
+}
+
+// onExpire reports item, which has just been removed, as expired. It must be
+// called before item is released.
+func (c *LRUCache[K, V]) onExpire(item *CacheItem[K, V]) {
+	c.stats.expirations.Add(1)
+	c.notify(EventExpire, item.key)
+	c.publish(EventExpire, item.key)
+	if c.options.drainExpired != nil {
+		value, _ := c.valueOf(item)
+		entry := ExpiredEntry[K, V]{Key: item.key, Value: value}
+		if c.options.drainPolicy == DrainBlock {
+			c.options.drainExpired <- entry
+		} else {
+			select {
+			case c.options.drainExpired <- entry:
+			default:
+				c.stats.drainDrops.Add(1)
+			}
+		}
+	}
+}
+
+func (c *LRUCache[K, V]) onRemove(key K) {
+	c.publish(EventRemove, key)
+	c.notify(EventRemove, key)
+}
+
+// onError reports a failure of background work to the WithErrorHandler
+// handler.
+func (c *LRUCache[K, V]) onError(err error) {
+	if c.options.errorHandler != nil {
+		c.options.errorHandler(err)
+	}
+}
+
+func (c *LRUCache[K, V]) onReject(key K) {
+	c.stats.rejections.Add(1)
+	c.publish(EventReject, key)
+	c.notify(EventReject, key)
+}
+
+func (c *LRUCache[K, V]) onRefresh(key K) {
+	c.stats.refreshes.Add(1)
+	c.publish(EventRefresh, key)
+	c.notify(EventRefresh, key)
+}
+
+// Stats returns the cache's counters along with its current size.
+func (c *LRUCache[K, V]) Stats() CacheStats {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.readStats((*atomic.Uint64).Load)
+}
+
+// ResetStats returns the cache's statistics like Stats and zeroes the
+// counters, so that the next call covers only what happened in between. Each
+// event is counted in exactly one of the two, however it races with the
+// reset. Sizes and other gauges are not counters and are left alone.
+func (c *LRUCache[K, V]) ResetStats() CacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := c.readStats(func(counter *atomic.Uint64) uint64 { return counter.Swap(0) })
+	// Move the WithAutoResize baseline along with the counters; the
+	// subtraction wraps, which keeps the next window's difference exact.
+	c.resizeWindow.hits -= stats.Hits
+	c.resizeWindow.misses -= stats.Misses
+	return stats
+}
+
+// readStats assembles the statistics, reading each counter with read. The
+// caller must hold the read or the write lock.
+func (c *LRUCache[K, V]) readStats(read func(*atomic.Uint64) uint64) CacheStats {
+	var refreshWorkers, refreshQueued int
+	if c.refresher != nil {
+		refreshWorkers = c.refresher.workers
+		refreshQueued = c.refresher.queued()
+	}
+
+	var lastSnapshot time.Time
+	if nanos := c.stats.lastSnapshot.Load(); nanos != 0 {
+		lastSnapshot = time.Unix(0, nanos)
+	}
+	return CacheStats{
+		Hits:         read(&c.stats.hits),
+		Misses:       read(&c.stats.misses),
+		Evictions:    read(&c.stats.evictions),
+		Expirations:  read(&c.stats.expirations),
+		LoadTimeouts: read(&c.stats.loadTimeouts),
+		DrainDrops:   read(&c.stats.drainDrops),
+		Rejections:   read(&c.stats.rejections),
+		Refreshes:    read(&c.stats.refreshes),
+		Size:         len(c.cache),
+		Capacity:     c.capacity,
+		Weight:       c.weight,
+		MaxWeight:    c.options.maxWeight,
+		Pinned:       c.pinned,
+
+		RefreshWorkers: refreshWorkers,
+		RefreshQueued:  refreshQueued,
+		RefreshSkips:   read(&c.stats.refreshSkips),
+
+		SnapshotDuration: time.Duration(c.stats.snapshotDuration.Load()),
+		LastSnapshot:     lastSnapshot,
+	}
+}
+
+// Entries returns metadata for every resident entry, most recently used first.
+func (c *LRUCache[K, V]) Entries() []EntryInfo[K] {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	entries := make([]EntryInfo[K], 0, len(c.cache))
+	c.policy.entries(func(item *CacheItem[K, V]) bool {
+		entries = append(entries, EntryInfo[K]{
+			Key:        item.key,
+			LastAccess: item.lastAccess(),
+			ExpiresAt:  item.expiresAt(),
+		})
+		return true
+	})
+	return entries
+}
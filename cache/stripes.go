@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// lockStripes serializes work per key over a fixed set of mutexes. Keys that
+// hash to different stripes never wait for each other; keys that share a
+// stripe do, which is the price of not keeping a mutex per key.
+type lockStripes[K comparable] struct {
+	hasher func(K) uint64
+	locks  []sync.Mutex
+}
+
+func newLockStripes[K comparable](stripes int, hasher func(K) uint64) *lockStripes[K] {
+	if hasher == nil {
+		hasher = defaultHasher[K]()
+	}
+	return &lockStripes[K]{hasher: hasher, locks: make([]sync.Mutex, stripes)}
+}
+
+// lock locks the stripe of key and returns the function that unlocks it.
+func (s *lockStripes[K]) lock(key K) func() {
+	stripe := &s.locks[s.hasher(key)%uint64(len(s.locks))]
+	stripe.Lock()
+	return stripe.Unlock
+}
+
+// keyLockStripes is the number of stripes behind LockKey when WithLockStripes
+// does not set one.
+const keyLockStripes = 256
+
+// LockKey locks key for the caller's own coordination, for example to update
+// a database and then the cache without another writer of the key slipping in
+// between, and returns the function that unlocks it. The locks are striped,
+// so their memory is bounded however many keys there are, and two keys may
+// share a lock. Only other LockKey callers wait: the cache's own operations
+// never take these locks, so they can be called for any key, including key,
+// while it is locked. Locks are not reentrant; locking a key already held by
+// the caller, or one that shares its stripe, deadlocks.
+func (c *LRUCache[K, V]) LockKey(key K) (unlock func()) {
+	c.keyLocksOnce.Do(func() {
+		stripes := c.options.lockStripes
+		if stripes == 0 {
+			stripes = keyLockStripes
+		}
+		c.keyLocks = newLockStripes[K](stripes, c.options.hasher)
+	})
+	return c.keyLocks.lock(c.normalize(key))
+}
+
+// fetchSerialized is fetch for a key that missed, with WithLockStripes making
+// concurrent misses and reloads of the key share one load rather than each
+// calling the backing store. No lock is held while the loader runs, so a
+// loader is free to read other keys of the cache. A caller that joined the
+// load of another is served its value, with the TTL it was stored with.
+func (c *LRUCache[K, V]) fetchSerialized(ctx context.Context, key K, prior time.Duration) (V, time.Duration, int, error) {
+	if c.options.lockStripes == 0 {
+		return c.fetch(ctx, key, prior)
+	}
+	var ttl time.Duration
+	var route int
+	value, err, loaded := c.share(key, reloadSlot[V], func() (V, error) {
+		var value V
+		var err error
+		value, ttl, route, err = c.fetch(ctx, key, prior)
+		return value, err
+	})
+	if !loaded {
+		_, ttl, _ = c.liveValue(key)
+	}
+	return value, ttl, route, err
+}
+
+// liveValue returns the value and TTL of key's entry if it is live, without
+// counting as a use.
+func (c *LRUCache[K, V]) liveValue(key K) (V, time.Duration, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var zeroValue V
+	item, found := c.cache[key]
+	if !found || item.expiredAt(c.now()) {
+		return zeroValue, 0, false
+	}
+	value, ok := c.read(item)
+	if !ok {
+		return zeroValue, 0, false
+	}
+	return value, item.expiry, true
+}
@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test Case 1: Concurrent misses of a key share a single load
+func TestLockStripesShareLoads(t *testing.T) {
+	var mutex sync.Mutex
+	loads := make(map[string]int)
+	loader := func(key string) (string, bool) {
+		mutex.Lock()
+		loads[key]++
+		mutex.Unlock()
+		time.Sleep(time.Millisecond)
+		return "value-" + key, true
+	}
+	cache, err := New(
+		WithLoader[string, string](loader),
+		WithListener[string, string](quietListener[string]{}),
+		WithLockStripes[string, string](4),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("key%d", i%10)
+			if value := cache.Get(key); value != "value-"+key {
+				t.Errorf("Expected 'value-%s', got '%s'", key, value)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range 10 {
+		key := fmt.Sprintf("key%d", i)
+		if value := loads[key]; value != 1 {
+			t.Errorf("Expected '1' load of %s, got '%d'", key, value)
+		}
+	}
+}
+
+// Test Case 2: A slow load does not hold up a key in another stripe
+func TestLockStripesIndependentKeys(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	loader := func(key string) (string, bool) {
+		if key == "slow" {
+			close(started)
+			<-release
+		}
+		return "value-" + key, true
+	}
+	stripeOf := func(key string) uint64 {
+		if key == "slow" {
+			return 0
+		}
+		return 1
+	}
+	cache, err := New(
+		WithLoader[string, string](loader),
+		WithListener[string, string](quietListener[string]{}),
+		WithLockStripes[string, string](2),
+		WithHasher[string, string](stripeOf),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	var done atomic.Bool
+	go func() {
+		cache.Get("slow")
+		done.Store(true)
+	}()
+	<-started
+	if value := cache.Get("fast"); value != "value-fast" {
+		t.Errorf("Expected 'value-fast', got '%s'", value)
+	}
+	if done.Load() {
+		t.Errorf("Expected the slow load to still be running")
+	}
+	close(release)
+}
+
+// Test Case 3: LockKey excludes other lockers of the key but not cache calls
+func TestLockKey(t *testing.T) {
+	cache := newTestCache(10, time.Minute, quietListener[string]{}).(*LRUCache[string, string])
+	defer cache.Close()
+
+	var counter int
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := cache.LockKey("counter")
+			defer unlock()
+			counter++ // Safe only because the key lock is held.
+			cache.Put("counter", fmt.Sprint(counter))
+		}()
+	}
+	wg.Wait()
+	if value := cache.Get("counter"); value != "50" {
+		t.Errorf("Expected '50', got '%s'", value)
+	}
+
+	unlock := cache.LockKey("keyX")
+	defer unlock()
+	if value := cache.Get("keyX"); value != "valueX" { // Loads while locked.
+		t.Errorf("Expected 'valueX', got '%s'", value)
+	}
+}
+
+// Test Case 4: A loader may read another key that shares its stripe
+func TestLockStripesNestedLoad(t *testing.T) {
+	var cache *LRUCache[string, string]
+	loader := func(key string) (string, bool) {
+		if key == "outer" {
+			return "outer-" + cache.Get("inner"), true
+		}
+		return "value-" + key, true
+	}
+	cache, err := New(
+		WithLoader[string, string](loader),
+		WithListener[string, string](quietListener[string]{}),
+		WithLockStripes[string, string](1),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+
+	done := make(chan string)
+	go func() {
+		done <- cache.Get("outer")
+	}()
+	select {
+	case value := <-done:
+		if value != "outer-value-inner" {
+			t.Errorf("Expected 'outer-value-inner', got '%s'", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the nested load to finish, but it deadlocked")
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"slices"
+	"time"
+)
+
+// PutTagged stores value under key like Put and attaches tags to the entry,
+// replacing any tags it had. Every entry carrying a tag can later be dropped
+// with InvalidateTag. A plain Put leaves an entry's tags unchanged.
+func (c *LRUCache[K, V]) PutTagged(key K, value V, tags []string, ttl ...time.Duration) {
+	key = c.normalize(key)
+	defer c.publishInvalidation(true, key)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.put(key, value, c.expiryFor(ttl))
+	if item, found := c.cache[key]; found {
+		c.untag(item)
+		c.tag(item, tags)
+	}
+}
+
+// InvalidateTag removes every entry carrying tag, including entries spilled
+// by WithSpill, and returns how many were removed. Each key is removed as
+// Remove removes it.
+func (c *LRUCache[K, V]) InvalidateTag(tag string) int {
+	var removed []K
+	defer func() { c.publishInvalidation(false, removed...) }()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key := range c.tags[tag] {
+		removed = append(removed, key)
+	}
+	removed = append(removed, c.spilledKeys(func(_ K, entry spilledEntry) bool {
+		return slices.Contains(entry.tags, tag)
+	})...)
+	for _, key := range removed {
+		c.removeKey(key)
+	}
+	return len(removed)
+}
+
+// tag attaches tags to item and records it in the tag index.
+func (c *LRUCache[K, V]) tag(item *CacheItem[K, V], tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+	if c.tags == nil {
+		c.tags = make(map[string]map[K]struct{})
+	}
+	item.tags = slices.Compact(slices.Sorted(slices.Values(tags)))
+	for _, tag := range item.tags {
+		keys, found := c.tags[tag]
+		if !found {
+			keys = make(map[K]struct{})
+			c.tags[tag] = keys
+		}
+		keys[item.key] = struct{}{}
+	}
+}
+
+// untag removes item from the tag index, dropping tags that no longer have
+// any entries.
+func (c *LRUCache[K, V]) untag(item *CacheItem[K, V]) {
+	for _, tag := range item.tags {
+		delete(c.tags[tag], item.key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+	item.tags = nil
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vivekkothari/in-memory-cache/cache/testutil/clocktest"
+)
+
+// Test Case 1: InvalidateTag removes exactly the tagged entries
+func TestInvalidateTag(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, listener, 5*time.Second)
+
+	cache.PutTagged("key1", "value1", []string{"request-1"})
+	cache.PutTagged("key2", "value2", []string{"request-1", "user-7"})
+	cache.PutTagged("key3", "value3", []string{"request-2"})
+	cache.Put("key4", "value4")
+
+	if removed := cache.InvalidateTag("request-1"); removed != 2 {
+		t.Errorf("Expected '2', got '%d'", removed)
+	}
+	if keys := cache.Keys(); len(keys) != 2 || keys[0] != "key4" || keys[1] != "key3" {
+		t.Errorf("Expected [key4 key3], got %v", keys)
+	}
+	if value := listener.removeMap["key2"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if removed := cache.InvalidateTag("user-7"); removed != 0 {
+		t.Errorf("Expected '0', got '%d'", removed)
+	}
+	if value := len(cache.tags); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 2: Evicted and retagged entries leave the index
+func TestTagIndexMaintenance(t *testing.T) {
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+
+	cache.PutTagged("key1", "value1", []string{"old"})
+	cache.PutTagged("key1", "value1", []string{"new"})
+	cache.Put("key1", "updated") // A plain Put keeps the tags
+	if removed := cache.InvalidateTag("old"); removed != 0 {
+		t.Errorf("Expected '0', got '%d'", removed)
+	}
+
+	cache.PutTagged("key2", "value2", []string{"new"})
+	cache.Put("key3", "value3") // Evicts key1
+	if removed := cache.InvalidateTag("new"); removed != 1 {
+		t.Errorf("Expected '1', got '%d'", removed)
+	}
+	if value := cache.Len(); value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 3: Expired, removed and cleared entries leave the index
+func TestTagIndexLeavingPaths(t *testing.T) {
+	clock := clocktest.New(time.Now())
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, quietListener[string]{}, time.Second, WithClock[string, string](clock))
+	defer cache.Close()
+
+	cache.PutTagged("expiring", "value", []string{"product-42"}, time.Second)
+	cache.PutTagged("removed", "value", []string{"product-42"})
+	cache.PutTagged("kept", "value", []string{"product-42", "product-7"})
+	clock.Advance(2 * time.Second) // Runs the cleanup goroutine
+	cache.Remove("removed")
+
+	if keys := cache.tags["product-42"]; len(keys) != 1 {
+		t.Errorf("Expected only 'kept' under product-42, got %v", keys)
+	}
+	if err := cache.checkInvariants(); err != nil {
+		t.Errorf("Expected a consistent tag index, got '%v'", err)
+	}
+	cache.Clear()
+	if value := len(cache.tags); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 4: A sharded cache invalidates a tag across all its shards
+func TestShardedInvalidateTag(t *testing.T) {
+	cache := NewShardedLRUCache[string, string](4, 40, 5*time.Second, nil, quietListener[string]{}, 5*time.Second)
+	defer cache.Close()
+
+	for _, key := range []string{"search-1", "search-2", "search-3", "search-4", "search-5"} {
+		cache.PutTagged(key, "value", []string{"product-42"})
+	}
+	cache.Put("search-6", "value")
+
+	if removed := cache.InvalidateTag("product-42"); removed != 5 {
+		t.Errorf("Expected '5', got '%d'", removed)
+	}
+	if keys := cache.Keys(); len(keys) != 1 || keys[0] != "search-6" {
+		t.Errorf("Expected [search-6], got %v", keys)
+	}
+}
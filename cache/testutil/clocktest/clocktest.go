@@ -0,0 +1,94 @@
+// Package clocktest provides a fake cache.Clock for tests that need to control
+// expiry and background cleanup without sleeping.
+package clocktest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a clock that only moves when Advance is called. Its zero value
+// is not usable; create one with New.
+type FakeClock struct {
+	mutex   sync.Mutex
+	now     time.Time
+	tickers []*ticker
+}
+
+type ticker struct {
+	c      chan time.Time
+	period time.Duration
+	next   time.Time
+	done   chan struct{}
+}
+
+// New returns a FakeClock that reads now until it is advanced.
+func New(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// NewTicker returns a ticker that fires every d of fake time, as the clock is
+// advanced. Its channel is unbuffered so that Advance can hand each tick over
+// to the receiver.
+func (c *FakeClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	if d <= 0 {
+		panic("clocktest: non-positive interval for NewTicker")
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	t := &ticker{c: make(chan time.Time), period: d, next: c.now.Add(d), done: make(chan struct{})}
+	c.tickers = append(c.tickers, t)
+	return t.c, func() { c.stop(t) }
+}
+
+func (c *FakeClock) stop(t *ticker) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, other := range c.tickers {
+		if other == t {
+			c.tickers = append(c.tickers[:i], c.tickers[i+1:]...)
+			close(t.done)
+			return
+		}
+	}
+}
+
+// Advance moves the clock forward by d and fires every tick that falls due,
+// in order of time. Unlike a real ticker, which drops ticks nobody is ready
+// for, Advance waits for each tick to be received, or for its ticker to be
+// stopped, before returning, so the goroutine reading the ticker has always
+// started on the tick by then. Advance must not be called concurrently.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	tickers := append([]*ticker(nil), c.tickers...)
+	c.mutex.Unlock()
+
+	for {
+		due := -1
+		for i, t := range tickers {
+			if !t.next.After(now) && (due < 0 || t.next.Before(tickers[due].next)) {
+				due = i
+			}
+		}
+		if due < 0 {
+			return
+		}
+		t := tickers[due]
+		at := t.next
+		t.next = at.Add(t.period)
+		select {
+		case t.c <- at:
+		case <-t.done:
+			tickers = append(tickers[:due], tickers[due+1:]...)
+		}
+	}
+}
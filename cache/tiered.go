@@ -0,0 +1,50 @@
+package cache
+
+import "time"
+
+type tieredCache[K comparable, V any] struct {
+	l1 Cache[K, V]
+	l2 Cache[K, V]
+}
+
+// Tiered combines a small, fast l1 cache with a larger l2 cache. A Get that
+// misses l1 consults l2 (and, through it, l2's own backing store) and promotes
+// any value found there into l1. Writes and removals go to both tiers.
+//
+// Since l2 already acts as l1's backing store, l1 is expected to be built
+// without one. Alternatively, l2.GetOk can be passed directly as l1's backing
+// store when constructing it.
+func Tiered[K comparable, V any](l1, l2 Cache[K, V]) Cache[K, V] {
+	return &tieredCache[K, V]{l1: l1, l2: l2}
+}
+
+func (t *tieredCache[K, V]) Put(key K, value V, ttl ...time.Duration) {
+	t.l2.Put(key, value, ttl...)
+	t.l1.Put(key, value, ttl...)
+}
+
+func (t *tieredCache[K, V]) Get(key K) V {
+	value, _ := t.GetOk(key)
+	return value
+}
+
+func (t *tieredCache[K, V]) GetOk(key K) (V, bool) {
+	if value, found := t.l1.GetOk(key); found {
+		return value, true
+	}
+	value, found := t.l2.GetOk(key)
+	if found {
+		t.l1.Put(key, value)
+	}
+	return value, found
+}
+
+func (t *tieredCache[K, V]) Remove(key K) {
+	t.l1.Remove(key)
+	t.l2.Remove(key)
+}
+
+func (t *tieredCache[K, V]) Close() {
+	t.l1.Close()
+	t.l2.Close()
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// Test Case 1: L2 hit is promoted into L1
+func TestTieredL2HitPopulatesL1(t *testing.T) {
+	l1Listener := NewCountingCacheListener[string]()
+	l1 := NewLRUCache[string, string](2, 5*time.Second, nil, l1Listener, 5*time.Second)
+	l2 := newTestCache(10, 5*time.Second, NewCountingCacheListener[string]())
+	tiered := Tiered[string, string](l1, l2)
+
+	l2.Put("key1", "value1")
+
+	if value := tiered.Get("key1"); value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+	if value, found := l1.GetOk("key1"); !found || value != "value1" {
+		t.Errorf("Expected 'value1' to be promoted into L1, got '%s' (found=%t)", value, found)
+	}
+	if value := l1Listener.hitMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+}
+
+// Test Case 2: L2 miss falls through to L2's backing store
+func TestTieredL2BackingStore(t *testing.T) {
+	l1 := NewLRUCache[string, string](2, 5*time.Second, nil, NewCountingCacheListener[string](), 5*time.Second)
+	l2 := newTestCache(10, 5*time.Second, NewCountingCacheListener[string]())
+	tiered := Tiered[string, string](l1, l2)
+
+	if value, found := tiered.GetOk("keyX"); !found || value != "valueX" {
+		t.Errorf("Expected 'valueX', got '%s' (found=%t)", value, found)
+	}
+	if value, found := l1.GetOk("keyX"); !found || value != "valueX" {
+		t.Errorf("Expected 'valueX' in L1, got '%s' (found=%t)", value, found)
+	}
+	if _, found := tiered.GetOk("keyY"); found {
+		t.Errorf("Expected 'keyY' to be absent from every tier")
+	}
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// NewLRUCacheChecked is NewLRUCache with validation: instead of accepting a
+// configuration that would misbehave later, it returns an error describing
+// every problem it finds in the arguments and options.
+func NewLRUCacheChecked[K comparable, V any](capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration, opts ...Option[K, V]) (*LRUCache[K, V], error) {
+	if err := validateConfig(capacity, defaultTTL, backingStore != nil, cleanupInterval, opts); err != nil {
+		return nil, err
+	}
+	return NewLRUCache(capacity, defaultTTL, backingStore, cacheListener, cleanupInterval, opts...), nil
+}
+
+// NewShardedLRUCacheChecked is NewShardedLRUCache with the validation of
+// NewLRUCacheChecked, plus a check of the shard count.
+func NewShardedLRUCacheChecked[K comparable, V any](shards int, capacity int, defaultTTL time.Duration, backingStore func(K) (V, bool), cacheListener CacheListener[K], cleanupInterval time.Duration, opts ...Option[K, V]) (*ShardedLRUCache[K, V], error) {
+	err := validateConfig(capacity, defaultTTL, backingStore != nil, cleanupInterval, opts)
+	if shards < 1 {
+		err = errors.Join(fmt.Errorf("cache: shard count must be at least 1, got %d", shards), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewShardedLRUCache(shards, capacity, defaultTTL, backingStore, cacheListener, cleanupInterval, opts...), nil
+}
+
+// validateConfig checks the constructor arguments and opts; hasBackingStore
+// reports whether a backing store was passed.
+func validateConfig[K comparable, V any](capacity int, defaultTTL time.Duration, hasBackingStore bool, cleanupInterval time.Duration, opts []Option[K, V]) error {
+	o := collectOptions(opts)
+
+	errs := []error{o.validateUsable(cleanupInterval)}
+	if capacity < 0 {
+		errs = append(errs, fmt.Errorf("cache: capacity must not be negative, got %d; use 0 for an unbounded cache", capacity))
+	}
+	if defaultTTL < 0 {
+		errs = append(errs, fmt.Errorf("cache: default TTL must not be negative, got %v", defaultTTL))
+	}
+	if o.refreshWindow > 0 && !hasBackingStore && !o.hasLoader() {
+		errs = append(errs, errors.New("cache: WithRefreshAhead needs a backing store or loader to refresh from"))
+	}
+	return errors.Join(append(errs, o.validate()...)...)
+}
+
+// validateUsable reports configurations that cannot work at all. NewLRUCache
+// panics on them rather than failing later in the cleanup goroutine.
+func (o *options[K, V]) validateUsable(cleanupInterval time.Duration) error {
+	var errs []error
+	if cleanupInterval <= 0 {
+		errs = append(errs, fmt.Errorf("cache: cleanup interval must be positive, got %v", cleanupInterval))
+	}
+	if o.snapshotWriter != nil && o.snapshotInterval <= 0 {
+		errs = append(errs, fmt.Errorf("cache: WithPeriodicSnapshot interval must be positive, got %v", o.snapshotInterval))
+	}
+	if o.snapshotPath != "" && o.snapshotInterval <= 0 {
+		errs = append(errs, fmt.Errorf("cache: WithSnapshotInterval interval must be positive, got %v", o.snapshotInterval))
+	}
+	if o.autoResize != nil && o.autoResize.MinCapacity > o.autoResize.MaxCapacity {
+		errs = append(errs, fmt.Errorf("cache: WithAutoResize minimum capacity %d exceeds maximum %d", o.autoResize.MinCapacity, o.autoResize.MaxCapacity))
+	}
+	if o.codec == nil {
+		errs = append(errs, errors.New("cache: WithCodec needs a codec"))
+	}
+	if o.cloneOnPut && o.cloneValue == nil {
+		errs = append(errs, errors.New("cache: WithValueCloner needs a clone function"))
+	}
+	if o.asyncListener && (o.listenerWorkers < 1 || o.listenerBuffer < 0) {
+		errs = append(errs, fmt.Errorf("cache: WithAsyncListener needs at least one worker and a non-negative buffer, got %d and %d", o.listenerWorkers, o.listenerBuffer))
+	}
+	if o.refreshWindow < 0 || (o.refreshWindow > 0 && (o.refreshWorkers < 1 || o.refreshQueue < 0)) {
+		errs = append(errs, fmt.Errorf("cache: WithRefreshAhead needs a positive window, at least one worker and a non-negative queue, got %v, %d and %d", o.refreshWindow, o.refreshWorkers, o.refreshQueue))
+	}
+	if o.lockStripes < 0 {
+		errs = append(errs, fmt.Errorf("cache: WithLockStripes needs a non-negative number of stripes, got %d", o.lockStripes))
+	}
+	if o.removeHold < 0 {
+		errs = append(errs, fmt.Errorf("cache: WithRemoveHold needs a non-negative hold, got %v", o.removeHold))
+	}
+	for i, route := range o.loaderRoutes {
+		if route.match == nil || route.loader == nil {
+			errs = append(errs, fmt.Errorf("cache: WithLoaderFor route %d needs a match function and a loader", i+1))
+		}
+	}
+	if o.clock == nil {
+		errs = append(errs, errors.New("cache: WithClock needs a clock"))
+	}
+	return errors.Join(errs...)
+}
+
+// hasLoader reports whether any of the loader options is set.
+func (o *options[K, V]) hasLoader() bool {
+	return o.loader != nil || o.loaderE != nil || o.loaderContext != nil || o.loaderTTL != nil ||
+		o.prefetchLoader != nil || len(o.loaderRoutes) > 0
+}
+
+// validate reports option values that are accepted but cannot mean what the
+// caller intended.
+func (o *options[K, V]) validate() []error {
+	var errs []error
+	if o.policy < LRU || o.policy > Scored {
+		errs = append(errs, fmt.Errorf("cache: unknown policy %d", o.policy))
+	}
+	if o.admission < AdmitAll || o.admission > TinyLFU {
+		errs = append(errs, fmt.Errorf("cache: unknown admission %d", o.admission))
+	}
+	if o.cleanupStrategy == SampledCleanup && (o.sampleSize < 1 || o.maxSampleRounds < 1) {
+		errs = append(errs, fmt.Errorf("cache: WithSampledCleanup needs a positive sample size and round count, got %d and %d", o.sampleSize, o.maxSampleRounds))
+	}
+	if o.negativeCapacity > 0 && o.negativeTTL <= 0 {
+		errs = append(errs, fmt.Errorf("cache: WithNegativeCache TTL must be positive, got %v", o.negativeTTL))
+	}
+	if o.promoteOneIn < 0 {
+		errs = append(errs, fmt.Errorf("cache: WithApproximateLRU must not be negative, got %d", o.promoteOneIn))
+	}
+	if o.loadTimeout < 0 {
+		errs = append(errs, fmt.Errorf("cache: WithLoadTimeout must not be negative, got %v", o.loadTimeout))
+	}
+	if o.policy == SLRU && (o.probationRatio <= 0 || o.probationRatio >= 1) {
+		errs = append(errs, fmt.Errorf("cache: WithProbationRatio must be between 0 and 1, got %v", o.probationRatio))
+	}
+	if o.policy == Scored && o.score == nil {
+		errs = append(errs, errors.New("cache: the Scored policy needs a score function from WithScoring"))
+	}
+	if (o.policy == SampledLRU || o.policy == Scored) && o.evictionSamples < 1 {
+		errs = append(errs, fmt.Errorf("cache: WithEvictionSamples must be positive, got %d", o.evictionSamples))
+	}
+	if o.weigher == nil && o.maxWeight != 0 {
+		errs = append(errs, errors.New("cache: WithMaximumWeight needs a weigher"))
+	}
+	if o.evictionRateWindow < time.Second {
+		errs = append(errs, fmt.Errorf("cache: WithEvictionRateWindow must be at least a second, got %v", o.evictionRateWindow))
+	}
+	if o.maxConcurrentLoads < 0 {
+		errs = append(errs, fmt.Errorf("cache: WithMaxConcurrentLoads must not be negative, got %d", o.maxConcurrentLoads))
+	}
+	if o.heapLimit > 0 && (o.shedFraction <= 0 || o.shedFraction > 1) {
+		errs = append(errs, fmt.Errorf("cache: WithMemoryPressure shed fraction must be in (0, 1], got %v", o.shedFraction))
+	}
+	if o.compressThreshold < 0 {
+		errs = append(errs, fmt.Errorf("cache: WithCompression threshold must not be negative, got %d", o.compressThreshold))
+	}
+	if o.lowWatermark < 0 || o.lowWatermark >= 1 {
+		errs = append(errs, fmt.Errorf("cache: WithWatermarks low watermark must be between 0 and 1, got %v", o.lowWatermark))
+	}
+	if o.weigher != nil && o.maxWeight <= 0 {
+		errs = append(errs, fmt.Errorf("cache: maximum weight must be positive, got %d", o.maxWeight))
+	}
+	return errs
+}
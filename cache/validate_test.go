@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test Case 1: A valid configuration builds a cache
+func TestCheckedConstructorValid(t *testing.T) {
+	cache, err := NewLRUCacheChecked[string, string](5, time.Second, nil, nil, time.Second,
+		WithPolicy[string, string](SLRU))
+	if err != nil {
+		t.Fatalf("Expected no error, got '%v'", err)
+	}
+	defer cache.Close()
+	cache.Put("key1", "value1")
+	if value := cache.Get("key1"); value != "value1" {
+		t.Errorf("Expected 'value1', got '%s'", value)
+	}
+}
+
+// Test Case 2: Every problem is reported
+func TestCheckedConstructorErrors(t *testing.T) {
+	_, err := NewLRUCacheChecked[string, string](-1, -time.Second, nil, nil, 0,
+		WithNegativeCache[string, string](10, 0),
+		WithMaximumWeight[string, string](0, byLength))
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+	for _, expected := range []string{"capacity", "default TTL", "cleanup interval", "WithNegativeCache", "maximum weight"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error to mention '%s', got '%v'", expected, err)
+		}
+	}
+
+	if _, err := NewShardedLRUCacheChecked[string, string](0, 10, time.Second, nil, nil, time.Second); err == nil || !strings.Contains(err.Error(), "shard count") {
+		t.Errorf("Expected a shard count error, got '%v'", err)
+	}
+}
+
+// Test Case 3: The unchecked constructor panics clearly on unusable configurations
+func TestUncheckedConstructorPanics(t *testing.T) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil || !strings.Contains(recovered.(error).Error(), "cleanup interval must be positive") {
+			t.Errorf("Expected a cleanup interval panic, got '%v'", recovered)
+		}
+	}()
+	NewLRUCache[string, string](5, time.Second, nil, nil, 0)
+}
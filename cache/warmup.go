@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Warmup loads keys from the backing store and stores the values found, with
+// the TTL a read-through load would give them, so that the cache is filled
+// before it takes traffic. Keys already holding a live entry or held by
+// WithRemoveHold are skipped, and so are keys stored or removed by someone
+// else while their load was in flight. Loads run
+// concurrently, up to the WithMaxConcurrentLoads limit or GOMAXPROCS when there
+// is none. These loads are not misses: they fire no OnMiss or OnLoad and leave
+// the hit and miss counters alone. Warmup stops handing out keys once ctx is
+// done, waits for the loads already started and returns ctx's error along
+// with the number of entries stored.
+func (c *LRUCache[K, V]) Warmup(ctx context.Context, keys []K) (loaded int, err error) {
+	workers := c.options.maxConcurrentLoads
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var stored atomic.Int64
+	var wg sync.WaitGroup
+	jobs := make(chan K)
+	for range min(workers, len(keys)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range jobs {
+				if c.resident(key) || c.held(key) {
+					continue
+				}
+				f := c.beginFill(key)
+				value, related, ttl, _, err := c.load(ctx, key)
+				if len(related) > 0 {
+					c.prefetch(key, related)
+				}
+				if err != nil {
+					c.endFill(key, f, value, ttl, err, nil)
+					continue
+				}
+				if c.storeIfAbsent(key, f, value, c.fillTTL(ttl, 0)) {
+					stored.Add(1)
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, key := range keys {
+		select {
+		case jobs <- c.normalize(key):
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return int(stored.Load()), err
+}
+
+// resident reports whether key, already normalized, holds a live entry.
+func (c *LRUCache[K, V]) resident(key K) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	item, found := c.cache[key]
+	return found && !item.expiredAt(c.now())
+}
+
+// storeIfAbsent stores value, loaded by the load f, under key, already
+// normalized, with ttl unless key holds a live entry or was stored, removed
+// or cleared since the load began, and reports whether it did.
+func (c *LRUCache[K, V]) storeIfAbsent(key K, f *fill[V], value V, ttl time.Duration) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.finishFill(key, f) {
+		return false
+	}
+	if item, found := c.cache[key]; found && !item.expiredAt(c.now()) {
+		return false
+	}
+	c.put(key, value, ttl)
+	_, found := c.cache[key]
+	return found
+}
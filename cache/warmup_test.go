@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test Case 1: Warmup loads missing keys without counting misses
+func TestWarmup(t *testing.T) {
+	var calls atomic.Int32
+	backingStore := func(key string) (string, bool) {
+		calls.Add(1)
+		if key == "missing" {
+			return "", false
+		}
+		return "loaded " + key, true
+	}
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](10, 5*time.Second, backingStore, listener, 5*time.Second)
+	defer cache.Close()
+	cache.Put("resident", "value")
+
+	loaded, err := cache.Warmup(context.Background(), []string{"key1", "key2", "resident", "missing"})
+	if err != nil || loaded != 2 {
+		t.Errorf("Expected 2 loaded and no error, got %d and %v", loaded, err)
+	}
+	if value := calls.Load(); value != 3 {
+		t.Errorf("Expected '3' store calls, got '%d'", value)
+	}
+	if value := cache.Get("resident"); value != "value" {
+		t.Errorf("Expected 'value', got '%s'", value)
+	}
+	if value := cache.Get("key1"); value != "loaded key1" {
+		t.Errorf("Expected 'loaded key1', got '%s'", value)
+	}
+	if stats := cache.Stats(); stats.Misses != 0 || len(listener.missMap) != 0 {
+		t.Errorf("Expected no misses, got %d and %v", stats.Misses, listener.missMap)
+	}
+}
+
+// Test Case 2: Warmup respects the load limit and stops when cancelled
+func TestWarmupConcurrencyAndCancel(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	ctx, cancel := context.WithCancel(context.Background())
+	backingStore := func(key string) (string, bool) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		if key == "key10" {
+			cancel()
+		}
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return key, true
+	}
+	cache := NewLRUCache[string, string](200, 5*time.Second, backingStore, quietListener[string]{}, 5*time.Second,
+		WithMaxConcurrentLoads[string, string](2))
+	defer cache.Close()
+
+	keys := make([]string, 100)
+	for i := range keys {
+		keys[i] = fmt.Sprint("key", i)
+	}
+	loaded, err := cache.Warmup(ctx, keys)
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if loaded < 10 || loaded > 20 || loaded != cache.Len() {
+		t.Errorf("Expected the warmup to stop soon after key10, got %d loaded and %d resident", loaded, cache.Len())
+	}
+	if peak > 2 {
+		t.Errorf("Expected at most '2' concurrent loads, got '%d'", peak)
+	}
+}
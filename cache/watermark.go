@@ -0,0 +1,16 @@
+package cache
+
+// lowWatermark is the entry count a batch eviction brings the cache down to.
+func (c *LRUCache[K, V]) lowWatermark() int {
+	return min(int(float64(c.capacity)*c.options.lowWatermark), c.capacity-1)
+}
+
+// entryLimit is the entry count above which Put evicts inline. It is the
+// capacity, except while batches run in the background: then the cache may
+// overshoot by one batch before Put stops waiting for the cleanup goroutine.
+func (c *LRUCache[K, V]) entryLimit() int {
+	if c.evictNow == nil {
+		return c.capacity
+	}
+	return 2*c.capacity - c.lowWatermark()
+}
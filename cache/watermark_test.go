@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Test Case 1: A full cache evicts down to the low watermark in one batch
+func TestWatermarkBatchEviction(t *testing.T) {
+	listener := &evictionLogListener{}
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, listener, 5*time.Second,
+		WithWatermarks[string, string](0.5, false))
+	defer cache.Close()
+
+	for i := 1; i <= 10; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+	cache.Put("key11", "value")
+
+	if !slices.Equal(listener.evicted, []string{"key1", "key2", "key3", "key4", "key5"}) {
+		t.Errorf("Expected [key1 key2 key3 key4 key5], got %v", listener.evicted)
+	}
+	if value := cache.Len(); value != 6 {
+		t.Errorf("Expected '6', got '%d'", value)
+	}
+
+	// The next Puts fill the freed room without evicting.
+	for i := 12; i <= 15; i++ {
+		cache.Put(fmt.Sprint("key", i), "value")
+	}
+	if value := len(listener.evicted); value != 5 {
+		t.Errorf("Expected '5', got '%d'", value)
+	}
+}
+
+// Test Case 2: Background batches are run by the cleanup goroutine
+func TestWatermarkBackgroundEviction(t *testing.T) {
+	cache := NewLRUCache[int, int](10, 5*time.Second, nil, quietListener[int]{}, 5*time.Second,
+		WithWatermarks[int, int](0.5, true))
+	defer cache.Close()
+
+	for i := 0; i < 11; i++ {
+		cache.Put(i, i)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Len() != 5 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if value := cache.Len(); value != 5 {
+		t.Errorf("Expected '5', got '%d'", value)
+	}
+	if value := cache.Stats().Evictions; value != 6 {
+		t.Errorf("Expected '6', got '%d'", value)
+	}
+	if _, found := cache.GetOk(10); !found {
+		t.Errorf("Expected the newest key to survive")
+	}
+}
+
+// Test Case 3: Put evicts inline once the overshoot reaches a full batch
+func TestWatermarkBackgroundBound(t *testing.T) {
+	cache := newLRUCache[int, int](10, 5*time.Second, nil, quietListener[int]{}, 5*time.Second,
+		WithWatermarks[int, int](0.5, true))
+	// No cleanup goroutine drains the signal, standing in for one that lags.
+	cache.evictNow = make(chan struct{}, 1)
+
+	for i := 0; i < 100; i++ {
+		cache.Put(i, i)
+	}
+	if value := cache.Len(); value > 15 {
+		t.Errorf("Expected at most '15', got '%d'", value)
+	}
+}
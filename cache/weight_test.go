@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func byLength(key, value string) int64 {
+	return int64(len(value))
+}
+
+// Test Case 1: Eviction keeps the total weight within the maximum
+func TestMaximumWeight(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, listener, 5*time.Second,
+		WithMaximumWeight[string, string](10, byLength))
+
+	cache.Put("key1", "aaaa")
+	cache.Put("key2", "bbbb")
+	cache.Get("key1")
+	cache.Put("key3", "cccccc") // Evicts key2, the least recently used
+	if value := listener.evictMap["key2"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Weight(); value != 10 {
+		t.Errorf("Expected '10', got '%d'", value)
+	}
+
+	cache.Put("key1", "aaaaaaa") // Growing key1 evicts key3
+	if value := listener.evictMap["key3"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	stats := cache.Stats()
+	if stats.Size != 1 || stats.Weight != 7 || stats.MaxWeight != 10 {
+		t.Errorf("Expected size 1 and weight 7 of 10, got %+v", stats)
+	}
+
+	cache.Remove("key1")
+	if value := cache.Weight(); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 2: An entry heavier than the maximum is rejected
+func TestMaximumWeightRejects(t *testing.T) {
+	listener := &rejectingListener{CountingCacheListener: NewCountingCacheListener[string]()}
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, listener, 5*time.Second,
+		WithMaximumWeight[string, string](5, byLength))
+
+	cache.Put("key1", "aaa")
+	cache.Put("huge", "aaaaaaaaaa")
+	if _, found := cache.GetOk("huge"); found {
+		t.Errorf("Expected 'huge' to be rejected")
+	}
+	cache.Put("key1", "aaaaaaaaaa") // Overwriting with a heavy value drops key1
+	if _, found := cache.GetOk("key1"); found {
+		t.Errorf("Expected 'key1' to be dropped")
+	}
+	if len(listener.rejected) != 2 || cache.Stats().Rejections != 2 {
+		t.Errorf("Expected [huge key1], got %v", listener.rejected)
+	}
+	if value := cache.Weight(); value != 0 {
+		t.Errorf("Expected '0', got '%d'", value)
+	}
+}
+
+// Test Case 3: The entry capacity still applies alongside the weight
+func TestMaximumWeightWithCapacity(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](2, 5*time.Second, nil, listener, 5*time.Second,
+		WithMaximumWeight[string, string](100, byLength))
+
+	cache.Put("key1", "a")
+	cache.Put("key2", "b")
+	cache.Put("key3", "c")
+	if value := listener.evictMap["key1"]; value != 1 {
+		t.Errorf("Expected '1', got '%d'", value)
+	}
+	if value := cache.Weight(); value != 2 {
+		t.Errorf("Expected '2', got '%d'", value)
+	}
+}
+
+// Test Case 4: PutE refuses a value heavier than the whole cache
+func TestPutEValueTooLarge(t *testing.T) {
+	listener := NewCountingCacheListener[string]()
+	cache := NewLRUCache[string, string](10, 5*time.Second, nil, listener, 5*time.Second,
+		WithMaximumWeight[string, string](10, byLength))
+	defer cache.Close()
+	cache.Put("key1", "aaaa")
+	cache.Put("key2", "bbbb")
+
+	if err := cache.PutE("key1", "way too heavy"); !errors.Is(err, ErrValueTooLarge) {
+		t.Errorf("Expected ErrValueTooLarge, got %v", err)
+	}
+	if err := cache.PutE("key3", "way too heavy"); !errors.Is(err, ErrValueTooLarge) {
+		t.Errorf("Expected ErrValueTooLarge, got %v", err)
+	}
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"key2", "key1"}) {
+		t.Errorf("Expected [key2 key1], got %v", keys)
+	}
+	if value := cache.Get("key1"); value != "aaaa" {
+		t.Errorf("Expected 'aaaa', got '%s'", value)
+	}
+	if stats := cache.Stats(); stats.Weight != 8 || stats.Evictions != 0 || stats.Rejections != 0 {
+		t.Errorf("Expected weight 8 and no evictions or rejections, got %+v", stats)
+	}
+
+	if err := cache.PutE("key3", "cc"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
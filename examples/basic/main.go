@@ -14,7 +14,16 @@ func main() {
 		return "", false
 	}
 
-	var cache cache2.Cache[string, string] = cache2.NewLRUCache[string, string](2, 5*time.Second, backingStore, nil, 5*time.Second)
+	lru, err := cache2.New(
+		cache2.WithCapacity[string, string](2),
+		cache2.WithDefaultTTL[string, string](5*time.Second),
+		cache2.WithLoader(backingStore),
+		cache2.WithCleanupInterval[string, string](5*time.Second),
+	)
+	if err != nil {
+		panic(err)
+	}
+	var cache cache2.Cache[string, string] = lru
 
 	cache.Put("key1", "value1")
 	fmt.Println(cache.Get("key1")) // Expected: value1